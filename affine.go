@@ -0,0 +1,180 @@
+// Copyright 2015 Simon HEGE. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package geom
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+)
+
+//Affine is a three-dimensional affine transformation, given as a matrix of 3 rows (x, y, z)
+//and 4 columns (x, y, z factors and offset), like the parameters of PostGIS ST_Affine:
+//
+//	x' = a[0][0]*x + a[0][1]*y + a[0][2]*z + a[0][3]
+//	y' = a[1][0]*x + a[1][1]*y + a[1][2]*z + a[1][3]
+//	z' = a[2][0]*x + a[2][1]*y + a[2][2]*z + a[2][3]
+//
+//Two-dimensional transformations leave the z row and column unchanged. The zero value is not the identity:
+//use NewAffine. The methods building transformations return a new Affine applying the receiver first,
+//so that NewAffine().Translate(-cx, -cy, 0).Rotate(angle).Translate(cx, cy, 0) rotates around (cx, cy).
+type Affine [3][4]float64
+
+//NewAffine returns the identity transformation
+func NewAffine() Affine {
+	return Affine{
+		{1, 0, 0, 0},
+		{0, 1, 0, 0},
+		{0, 0, 1, 0},
+	}
+}
+
+//Then returns the transformation applying a, then b
+func (a Affine) Then(b Affine) Affine {
+	var r Affine
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 4; j++ {
+			for k := 0; k < 3; k++ {
+				r[i][j] += b[i][k] * a[k][j]
+			}
+		}
+		r[i][3] += b[i][3]
+	}
+	return r
+}
+
+//Translate returns the transformation applying a, then a translation
+func (a Affine) Translate(dx, dy, dz float64) Affine {
+	t := NewAffine()
+	t[0][3], t[1][3], t[2][3] = dx, dy, dz
+	return a.Then(t)
+}
+
+//Scale returns the transformation applying a, then a scaling relative to the origin
+func (a Affine) Scale(sx, sy, sz float64) Affine {
+	s := NewAffine()
+	s[0][0], s[1][1], s[2][2] = sx, sy, sz
+	return a.Then(s)
+}
+
+//Rotate returns the transformation applying a, then a counter-clockwise rotation around the Z axis.
+//The angle is in radians.
+func (a Affine) Rotate(angle float64) Affine {
+	sin, cos := math.Sincos(angle)
+	r := NewAffine()
+	r[0][0], r[0][1] = cos, -sin
+	r[1][0], r[1][1] = sin, cos
+	return a.Then(r)
+}
+
+//RotateX returns the transformation applying a, then a rotation around the X axis (from Y towards Z).
+//The angle is in radians.
+func (a Affine) RotateX(angle float64) Affine {
+	sin, cos := math.Sincos(angle)
+	r := NewAffine()
+	r[1][1], r[1][2] = cos, -sin
+	r[2][1], r[2][2] = sin, cos
+	return a.Then(r)
+}
+
+//RotateY returns the transformation applying a, then a rotation around the Y axis (from Z towards X).
+//The angle is in radians.
+func (a Affine) RotateY(angle float64) Affine {
+	sin, cos := math.Sincos(angle)
+	r := NewAffine()
+	r[0][0], r[0][2] = cos, sin
+	r[2][0], r[2][2] = -sin, cos
+	return a.Then(r)
+}
+
+//Shear returns the transformation applying a, then a shear in the XY plane:
+//x is moved by shx times y, and y by shy times x
+func (a Affine) Shear(shx, shy float64) Affine {
+	s := NewAffine()
+	s[0][1], s[1][0] = shx, shy
+	return a.Then(s)
+}
+
+//Inverse returns the inverse transformation, or an error if a is not invertible
+func (a Affine) Inverse() (Affine, error) {
+	//Cofactors of the linear part
+	var c [3][3]float64
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			i1, i2 := (i+1)%3, (i+2)%3
+			j1, j2 := (j+1)%3, (j+2)%3
+			c[i][j] = a[i1][j1]*a[i2][j2] - a[i1][j2]*a[i2][j1]
+		}
+	}
+	det := a[0][0]*c[0][0] + a[0][1]*c[0][1] + a[0][2]*c[0][2]
+	if det == 0 || math.IsNaN(det) || math.IsInf(det, 0) {
+		return Affine{}, fmt.Errorf("Affine transformation not invertible")
+	}
+
+	var r Affine
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			r[i][j] = c[j][i] / det
+		}
+	}
+	for i := 0; i < 3; i++ {
+		r[i][3] = -(r[i][0]*a[0][3] + r[i][1]*a[1][3] + r[i][2]*a[2][3])
+	}
+	return r, nil
+}
+
+//Transform returns the transformed point, its z being 0
+func (a Affine) Transform(pt Point) Point {
+	return Point{
+		X: a[0][0]*pt.X + a[0][1]*pt.Y + a[0][3],
+		Y: a[1][0]*pt.X + a[1][1]*pt.Y + a[1][3],
+	}
+}
+
+//TransformZ returns the transformed three-dimensional point
+func (a Affine) TransformZ(pt PointZ) PointZ {
+	return PointZ{
+		Point: Point{
+			X: a[0][0]*pt.X + a[0][1]*pt.Y + a[0][2]*pt.Z + a[0][3],
+			Y: a[1][0]*pt.X + a[1][1]*pt.Y + a[1][2]*pt.Z + a[1][3],
+		},
+		Z: a[2][0]*pt.X + a[2][1]*pt.Y + a[2][2]*pt.Z + a[2][3],
+	}
+}
+
+//Apply transforms g in place. For GeometryZ types, Z values are transformed too; for the others, z is taken as 0.
+//M values are unchanged. Slice based geometries (LineString, Polygon, ...) can be given by value,
+//as their points are modified in the underlying arrays.
+func (a Affine) Apply(g Geometry) error {
+	if g == nil {
+		return fmt.Errorf("Unsupported geometry type: %v", reflect.TypeOf(g))
+	}
+	if gz, ok := g.(GeometryZ); ok {
+		return gz.IterateZ(func(points []PointZ) error {
+			for i := range points {
+				points[i] = a.TransformZ(points[i])
+			}
+			return nil
+		})
+	}
+	return g.Iterate(func(points []Point) error {
+		for i := range points {
+			points[i] = a.Transform(points[i])
+		}
+		return nil
+	})
+}
+
+//ApplyCopy returns a transformed copy of g, of the same type, leaving g unchanged. See Apply.
+func (a Affine) ApplyCopy(g Geometry) (Geometry, error) {
+	if g == nil {
+		return nil, fmt.Errorf("Unsupported geometry type: %v", reflect.TypeOf(g))
+	}
+	c := cloneGeometry(g)
+	if err := a.Apply(c); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
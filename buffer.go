@@ -0,0 +1,331 @@
+// Copyright 2015 Simon HEGE. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package geom
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+)
+
+//CapStyle is the shape of the buffer at the end points of lines
+type CapStyle int
+
+//End cap styles
+const (
+	CapRound  CapStyle = iota //half circle
+	CapFlat                   //no extension beyond the end point
+	CapSquare                 //half square
+)
+
+//JoinStyle is the shape of the buffer at the outer side of line vertices
+type JoinStyle int
+
+//Join styles
+const (
+	JoinRound JoinStyle = iota //circle arc
+	JoinMitre                  //sharp corner, replaced by a bevel beyond the mitre limit
+	JoinBevel                  //corner cut by a straight segment
+)
+
+//BufferOptions holds the parameters of Buffer and OffsetCurve. The zero value gives the default options.
+type BufferOptions struct {
+	QuadrantSegments int //Number of segments used to approximate a quarter circle; 8 if zero or negative
+	EndCap           CapStyle
+	Join             JoinStyle
+	MitreLimit       float64 //Maximum ratio between the mitre length and the distance; 5 if zero or negative
+}
+
+//bufferBuilder generates the polygons whose union is the buffer of some linework
+type bufferBuilder struct {
+	distance float64
+	opts     BufferOptions
+	pieces   []Geometry
+}
+
+func newBufferBuilder(distance float64, opts *BufferOptions) *bufferBuilder {
+	b := &bufferBuilder{distance: distance}
+	if opts != nil {
+		b.opts = *opts
+	}
+	if b.opts.QuadrantSegments <= 0 {
+		b.opts.QuadrantSegments = 8
+	}
+	if b.opts.MitreLimit <= 0 {
+		b.opts.MitreLimit = 5
+	}
+	return b
+}
+
+//direction returns the unit vector from a to b
+func direction(a, b Point) Point {
+	l := math.Hypot(b.X-a.X, b.Y-a.Y)
+	return Point{X: (b.X - a.X) / l, Y: (b.Y - a.Y) / l}
+}
+
+//leftNormal returns the unit vector u rotated counter-clockwise by a quarter turn
+func leftNormal(u Point) Point {
+	return Point{X: -u.Y, Y: u.X}
+}
+
+//offset returns pt moved by d along the unit vector n
+func offset(pt, n Point, d float64) Point {
+	return Point{X: pt.X + d*n.X, Y: pt.Y + d*n.Y}
+}
+
+//arc returns the points of the circle arc around c, from the unit vector n1 to the unit vector n2,
+//sweeping by the given angle (counter-clockwise if positive).
+//The first and last points are exactly the ones given by offset.
+func (b *bufferBuilder) arc(c, n1, n2 Point, sweep float64) []Point {
+	steps := int(math.Ceil(math.Abs(sweep) / (math.Pi / 2 / float64(b.opts.QuadrantSegments))))
+	if steps < 1 {
+		steps = 1
+	}
+	start := math.Atan2(n1.Y, n1.X)
+	points := make([]Point, 0, steps+1)
+	points = append(points, offset(c, n1, b.distance))
+	for i := 1; i < steps; i++ {
+		a := start + sweep*float64(i)/float64(steps)
+		points = append(points, Point{X: c.X + b.distance*math.Cos(a), Y: c.Y + b.distance*math.Sin(a)})
+	}
+	return append(points, offset(c, n2, b.distance))
+}
+
+//join returns the points of the join around v, from the offset along n1 to the offset along n2.
+//n1 and n2 are the normals on the outer side of the corner, u1 is the incoming direction.
+//halfTurn is true if the line goes back on itself at v.
+func (b *bufferBuilder) join(v, u1, n1, n2 Point, halfTurn bool) []Point {
+	p1, p2 := offset(v, n1, b.distance), offset(v, n2, b.distance)
+	cos := n1.X*n2.X + n1.Y*n2.Y
+	switch b.opts.Join {
+	case JoinRound:
+		sweep := math.Atan2(n1.X*n2.Y-n1.Y*n2.X, cos)
+		if halfTurn {
+			//Sweep through the incoming direction
+			sweep = math.Copysign(math.Pi, n1.X*u1.Y-n1.Y*u1.X)
+		}
+		return b.arc(v, n1, n2, sweep)
+	case JoinMitre:
+		if m, ok := b.mitre(v, n1, n2, halfTurn); ok {
+			return []Point{p1, m, p2}
+		}
+	}
+	return []Point{p1, p2}
+}
+
+//mitre returns the mitre point of the join around v, and false if the join is not a mitre
+//or if the mitre limit is exceeded
+func (b *bufferBuilder) mitre(v, n1, n2 Point, halfTurn bool) (Point, bool) {
+	cos := n1.X*n2.X + n1.Y*n2.Y
+	if b.opts.Join != JoinMitre || halfTurn || 1+cos <= 0 || math.Sqrt(2/(1+cos)) > b.opts.MitreLimit {
+		return Point{}, false
+	}
+	m := Point{X: (n1.X + n2.X) / (1 + cos), Y: (n1.Y + n2.Y) / (1 + cos)}
+	return offset(v, m, b.distance), true
+}
+
+//addRing adds a closed polygon made of the points
+func (b *bufferBuilder) addRing(points ...Point) {
+	ring := make(LineString, 0, len(points)+1)
+	ring = append(ring, points...)
+	ring = append(ring, points[0])
+	b.pieces = append(b.pieces, Polygon{ring})
+}
+
+//addPoint adds the buffer of a single point
+func (b *bufferBuilder) addPoint(pt Point) {
+	switch b.opts.EndCap {
+	case CapRound:
+		circle := b.arc(pt, Point{X: 1}, Point{X: 1}, 2*math.Pi)
+		b.addRing(circle[:len(circle)-1]...)
+	case CapSquare:
+		d := b.distance
+		b.addRing(Point{X: pt.X - d, Y: pt.Y - d}, Point{X: pt.X + d, Y: pt.Y - d}, Point{X: pt.X + d, Y: pt.Y + d}, Point{X: pt.X - d, Y: pt.Y + d})
+	}
+}
+
+//addCap adds the end cap at v, u being the direction pointing away from the line
+func (b *bufferBuilder) addCap(v, u Point) {
+	n := leftNormal(u)
+	switch b.opts.EndCap {
+	case CapRound:
+		b.addRing(b.arc(v, n, Point{X: -n.X, Y: -n.Y}, -math.Pi)...)
+	case CapSquare:
+		p1, p2 := offset(v, n, b.distance), offset(v, n, -b.distance)
+		b.addRing(p1, offset(p1, u, b.distance), offset(p2, u, b.distance), p2)
+	}
+}
+
+//addJoin adds the join at v, between the segments [a, v] and [v, c]
+func (b *bufferBuilder) addJoin(a, v, c Point) {
+	u1, u2 := direction(a, v), direction(v, c)
+	turn := Orient2D(a, v, c)
+	if turn == 0 && u1.X*u2.X+u1.Y*u2.Y > 0 {
+		return
+	}
+	//The gap between the segment buffers is on the right side of left turns
+	n1, n2 := leftNormal(u1), leftNormal(u2)
+	if turn > 0 {
+		n1, n2 = Point{X: -n1.X, Y: -n1.Y}, Point{X: -n2.X, Y: -n2.Y}
+	}
+	b.addRing(append([]Point{v}, b.join(v, u1, n1, n2, turn == 0)...)...)
+}
+
+//addLine adds the buffer of a line without repeated points. Closed lines have no end caps.
+func (b *bufferBuilder) addLine(line []Point) {
+	if len(line) == 1 {
+		b.addPoint(line[0])
+		return
+	}
+	for i := 1; i < len(line); i++ {
+		n := leftNormal(direction(line[i-1], line[i]))
+		b.addRing(offset(line[i-1], n, b.distance), offset(line[i-1], n, -b.distance), offset(line[i], n, -b.distance), offset(line[i], n, b.distance))
+	}
+	for i := 1; i < len(line)-1; i++ {
+		b.addJoin(line[i-1], line[i], line[i+1])
+	}
+	last := len(line) - 1
+	if line[0] == line[last] && len(line) > 3 {
+		b.addJoin(line[last-1], line[0], line[1])
+		return
+	}
+	b.addCap(line[0], direction(line[1], line[0]))
+	b.addCap(line[last], direction(line[last-1], line[last]))
+}
+
+//polygonal returns the polygons as a Polygon (possibly empty) or a MultiPolygon
+func polygonal(mp MultiPolygon) Geometry {
+	switch len(mp) {
+	case 0:
+		return Polygon{}
+	case 1:
+		return mp[0]
+	}
+	return mp
+}
+
+//Buffer returns the area within distance of g, as a Polygon or MultiPolygon.
+//Geometry collections are handled recursively, and Z and M values are ignored.
+//
+//Circles are approximated by polygons. With a negative distance, polygons are shrunk (their holes grow)
+//and points and lines are ignored. A nil opts gives the default options: round caps and joins,
+//with 8 segments per quarter circle.
+func Buffer(g Geometry, distance float64, opts *BufferOptions) (Geometry, error) {
+	if g == nil {
+		return nil, fmt.Errorf("Unsupported geometry type: %v", reflect.TypeOf(g))
+	}
+
+	b := newBufferBuilder(math.Abs(distance), opts)
+	var polygons []Geometry
+	components(g, func(c Geometry) {
+		if c.IsEmpty() {
+			return
+		}
+		switch c.Dimension() {
+		case 0:
+			if distance > 0 {
+				c.Iterate(func(points []Point) error {
+					for _, pt := range points {
+						b.addPoint(pt)
+					}
+					return nil
+				})
+			}
+		case 1:
+			if distance > 0 {
+				c.Iterate(func(points []Point) error {
+					b.addLine(removeRepeated(points))
+					return nil
+				})
+			}
+		case 2:
+			polygons = append(polygons, c)
+			if distance != 0 {
+				c.Iterate(func(ring []Point) error {
+					ring = removeRepeated(ring)
+					if ring[0] != ring[len(ring)-1] {
+						ring = append(ring, ring[0])
+					}
+					b.addLine(ring)
+					return nil
+				})
+			}
+		}
+	})
+
+	if distance >= 0 {
+		mp, err := UnaryUnion(append(polygons, b.pieces...), 0)
+		if err != nil {
+			return nil, err
+		}
+		return polygonal(mp), nil
+	}
+
+	area, err := UnaryUnion(polygons, 0)
+	if err != nil {
+		return nil, err
+	}
+	border, err := UnaryUnion(b.pieces, 0)
+	if err != nil {
+		return nil, err
+	}
+	r, err := Difference(area, border)
+	if err != nil {
+		return nil, err
+	}
+	var mp MultiPolygon
+	components(r, func(c Geometry) {
+		if p, ok := c.(Polygon); ok && !p.IsEmpty() {
+			mp = append(mp, p)
+		}
+	})
+	return polygonal(mp), nil
+}
+
+//OffsetCurve returns the line at distance of l, on its left side if distance is positive and on its right side otherwise.
+//Only the Join, MitreLimit and QuadrantSegments options are used.
+//The offset curve may self-intersect when the distance is larger than the radius of curvature of the line.
+func OffsetCurve(l LineString, distance float64, opts *BufferOptions) LineString {
+	line := removeRepeated(l)
+	if len(line) < 2 {
+		return LineString{}
+	}
+	b := newBufferBuilder(math.Abs(distance), opts)
+	side := 1.0
+	if distance < 0 {
+		side = -1
+	}
+	normal := func(a, c Point) Point {
+		n := leftNormal(direction(a, c))
+		return Point{X: side * n.X, Y: side * n.Y}
+	}
+
+	curve := LineString{offset(line[0], normal(line[0], line[1]), b.distance)}
+	for i := 1; i < len(line)-1; i++ {
+		a, v, c := line[i-1], line[i], line[i+1]
+		u1, u2 := direction(a, v), direction(v, c)
+		n1, n2 := normal(a, v), normal(v, c)
+		turn := side * Orient2D(a, v, c)
+		switch {
+		case turn == 0 && u1.X*u2.X+u1.Y*u2.Y > 0:
+			//Collinear segments
+			curve = append(curve, offset(v, n1, b.distance))
+		case turn > 0:
+			//Inner side of the corner: intersection of the offset segments
+			cos := n1.X*n2.X + n1.Y*n2.Y
+			m := Point{X: (n1.X + n2.X) / (1 + cos), Y: (n1.Y + n2.Y) / (1 + cos)}
+			curve = append(curve, offset(v, m, b.distance))
+		default:
+			//The offsets of the segments are collinear with the mitre point, which is enough
+			if m, ok := b.mitre(v, n1, n2, turn == 0); ok {
+				curve = append(curve, m)
+				continue
+			}
+			curve = append(curve, b.join(v, u1, n1, n2, turn == 0)...)
+		}
+	}
+	last := len(line) - 1
+	return append(curve, offset(line[last], normal(line[last-1], line[last]), b.distance))
+}
@@ -0,0 +1,94 @@
+// Copyright 2015 Simon HEGE. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package geom
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestBufferArea(t *testing.T) {
+	//Area of the 32 sided polygon approximating the unit circle with 8 segments per quadrant
+	circle := 16 * math.Sin(math.Pi/16)
+	segment := LineString{{X: 0, Y: 0}, {X: 10, Y: 0}}
+	corner := LineString{{X: 0, Y: 0}, {X: 10, Y: 0}, {X: 10, Y: 10}}
+	square := Polygon{ring(0, 0, 10, 0, 10, 10, 0, 10)}
+	tests := []struct {
+		name     string
+		g        Geometry
+		distance float64
+		opts     *BufferOptions
+		area     float64
+	}{
+		{"point round", &Point{}, 1, nil, circle},
+		{"point square", &Point{}, 1, &BufferOptions{EndCap: CapSquare}, 4},
+		{"point flat", &Point{}, 1, &BufferOptions{EndCap: CapFlat}, 0},
+		{"segment round", segment, 1, nil, 20 + circle},
+		{"segment flat", segment, 1, &BufferOptions{EndCap: CapFlat}, 20},
+		{"segment square", segment, 1, &BufferOptions{EndCap: CapSquare}, 24},
+		{"u-turn", LineString{{X: 0, Y: 0}, {X: 10, Y: 0}, {X: 0, Y: 0}}, 1, nil, 20 + circle},
+		{"corner round", corner, 1, &BufferOptions{EndCap: CapFlat}, 39.5 + (circle/4 - 0.5)},
+		{"corner mitre", corner, 1, &BufferOptions{EndCap: CapFlat, Join: JoinMitre}, 40},
+		{"corner bevel", corner, 1, &BufferOptions{EndCap: CapFlat, Join: JoinBevel}, 39.5},
+		{"corner mitre limit", corner, 1, &BufferOptions{EndCap: CapFlat, Join: JoinMitre, MitreLimit: 1.2}, 39.5},
+		{"corner within mitre limit", corner, 1, &BufferOptions{EndCap: CapFlat, Join: JoinMitre, MitreLimit: 1.5}, 40},
+		{"square mitre", square, 1, &BufferOptions{Join: JoinMitre}, 144},
+		{"square round", square, 1, nil, 140 + circle},
+		{"square negative", square, -1, nil, 64},
+		{"square collapsed", square, -6, nil, 0},
+		{"hole negative", Polygon{square[0], ring(4, 4, 4, 6, 6, 6, 6, 4)}, -1, &BufferOptions{Join: JoinMitre}, 48},
+		{"hole closed", Polygon{square[0], ring(4, 4, 4, 6, 6, 6, 6, 4)}, 1, &BufferOptions{Join: JoinMitre}, 144},
+		{"closed line", LineString(square[0]), 1, &BufferOptions{Join: JoinMitre}, 80},
+	}
+	for _, tt := range tests {
+		g, err := Buffer(tt.g, tt.distance, tt.opts)
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if a := Area(g); math.Abs(a-tt.area) > 1e-9 {
+			t.Errorf("%s: area = %v, want %v", tt.name, a, tt.area)
+		}
+		if !IsValid(g) {
+			t.Errorf("%s: invalid result %v", tt.name, Validate(g))
+		}
+	}
+}
+
+func TestOffsetCurve(t *testing.T) {
+	corner := LineString{{X: 0, Y: 0}, {X: 10, Y: 0}, {X: 10, Y: 10}}
+	tests := []struct {
+		name     string
+		distance float64
+		opts     *BufferOptions
+		want     LineString
+	}{
+		{"inner side", 1, nil, LineString{{X: 0, Y: 1}, {X: 9, Y: 1}, {X: 9, Y: 10}}},
+		{"mitre", -1, &BufferOptions{Join: JoinMitre}, LineString{{X: 0, Y: -1}, {X: 11, Y: -1}, {X: 11, Y: 10}}},
+		{"mitre limit", -1, &BufferOptions{Join: JoinMitre, MitreLimit: 1.2}, LineString{{X: 0, Y: -1}, {X: 10, Y: -1}, {X: 11, Y: 0}, {X: 11, Y: 10}}},
+		{"bevel", -1, &BufferOptions{Join: JoinBevel}, LineString{{X: 0, Y: -1}, {X: 10, Y: -1}, {X: 11, Y: 0}, {X: 11, Y: 10}}},
+	}
+	for _, tt := range tests {
+		if got := OffsetCurve(corner, tt.distance, tt.opts); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: OffsetCurve() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+
+	//Round joins: arc from the offset of the first segment to the offset of the second one
+	round := OffsetCurve(corner, -1, nil)
+	if len(round) != 11 || round[1] != (Point{X: 10, Y: -1}) || round[9] != (Point{X: 11, Y: 0}) {
+		t.Errorf("OffsetCurve() with round join = %v", round)
+	}
+	for _, pt := range round[1:10] {
+		if d := math.Hypot(pt.X-10, pt.Y); math.Abs(d-1) > 1e-9 {
+			t.Errorf("OffsetCurve() with round join: %v at distance %v of the corner", pt, d)
+		}
+	}
+
+	if got := OffsetCurve(LineString{{X: 1, Y: 1}, {X: 1, Y: 1}}, 1, nil); len(got) != 0 {
+		t.Errorf("OffsetCurve() of a degenerate line = %v", got)
+	}
+}
@@ -0,0 +1,393 @@
+// Copyright 2015 Simon HEGE. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package geom
+
+import (
+	"container/heap"
+	"math"
+	"sort"
+)
+
+//centroidAccumulator accumulates the weighted coordinates of points, lines and areas
+type centroidAccumulator struct {
+	area, areaX, areaY       float64
+	length, lengthX, lengthY float64
+	count, pointX, pointY    float64
+}
+
+func (c *centroidAccumulator) addPoints(points []Point) {
+	for _, pt := range points {
+		if math.IsNaN(pt.X) || math.IsNaN(pt.Y) {
+			continue
+		}
+		c.count++
+		c.pointX += pt.X
+		c.pointY += pt.Y
+	}
+}
+
+func (c *centroidAccumulator) addLine(points []Point) {
+	for i := 1; i < len(points); i++ {
+		a, b := points[i-1], points[i]
+		l := math.Hypot(b.X-a.X, b.Y-a.Y)
+		c.length += l
+		c.lengthX += l * (a.X + b.X) / 2
+		c.lengthY += l * (a.Y + b.Y) / 2
+	}
+	c.addPoints(points)
+}
+
+//addRing adds the area of a ring, subtracted if hole is true
+func (c *centroidAccumulator) addRing(points []Point, hole bool) {
+	c.addLine(points)
+	if len(points) < 3 {
+		return
+	}
+	//Triangles fan relative to the first point to limit rounding errors
+	o := points[0]
+	area, x, y := 0.0, 0.0, 0.0
+	for i := 1; i < len(points)-1; i++ {
+		a, b := points[i], points[i+1]
+		cross := (a.X-o.X)*(b.Y-o.Y) - (b.X-o.X)*(a.Y-o.Y)
+		area += cross
+		x += cross * (a.X + b.X - 2*o.X)
+		y += cross * (a.Y + b.Y - 2*o.Y)
+	}
+	if area == 0 {
+		return
+	}
+	cx, cy := o.X+x/(3*area), o.Y+y/(3*area)
+	weight := math.Abs(area) / 2
+	if hole {
+		weight = -weight
+	}
+	c.area += weight
+	c.areaX += weight * cx
+	c.areaY += weight * cy
+}
+
+func (c *centroidAccumulator) add(g Geometry) {
+	if isCollection(g) {
+		for i := 0; i < g.NumGeometries(); i++ {
+			c.add(g.GeometryN(i))
+		}
+		return
+	}
+
+	switch g.Dimension() {
+	case 0:
+		g.Iterate(func(points []Point) error {
+			c.addPoints(points)
+			return nil
+		})
+	case 1:
+		g.Iterate(func(points []Point) error {
+			c.addLine(points)
+			return nil
+		})
+	case 2:
+		surfaces(g, func(s Surface) {
+			if s.IsEmpty() {
+				return
+			}
+			c.addRing(pointsOf(s.ExteriorRing()), false)
+			for i := 0; i < s.NumInteriorRings(); i++ {
+				c.addRing(pointsOf(s.InteriorRingN(i)), true)
+			}
+		})
+	}
+}
+
+//Centroid returns the center of mass of the geometry, or nil if the geometry is empty.
+//Only the components of the highest dimension are taken into account: polygons weighted by area,
+//then lines weighted by length, then points. Degenerate components fall back to the lower dimension.
+func Centroid(g Geometry) *Point {
+	var c centroidAccumulator
+	c.add(g)
+
+	switch {
+	case c.area != 0:
+		return &Point{X: c.areaX / c.area, Y: c.areaY / c.area}
+	case c.length > 0:
+		return &Point{X: c.lengthX / c.length, Y: c.lengthY / c.length}
+	case c.count > 0:
+		return &Point{X: c.pointX / c.count, Y: c.pointY / c.count}
+	}
+	return nil
+}
+
+//components calls f on the single-part components of a geometry, recursing through collections and multi-geometries
+func components(g Geometry, f func(g Geometry)) {
+	switch g.GeometryType() {
+	case "MultiPoint", "MultiLineString", "MultiPolygon", "GeometryCollection":
+		for i := 0; i < g.NumGeometries(); i++ {
+			components(g.GeometryN(i), f)
+		}
+	default:
+		f(g)
+	}
+}
+
+//dimension returns the highest dimension of the non-empty components of g, or -1 if g is empty
+func dimension(g Geometry) int {
+	d := -1
+	components(g, func(c Geometry) {
+		if !c.IsEmpty() && c.Dimension() > d {
+			d = c.Dimension()
+		}
+	})
+	return d
+}
+
+//PointOnSurface returns a point guaranteed to lie on the geometry, or nil if the geometry is empty.
+//For polygons, the point is inside the interior, in the middle of the widest horizontal section.
+//For lines, the interior vertex closest to the centroid is chosen, for points the closest point.
+//Only the components of the highest dimension are taken into account.
+func PointOnSurface(g Geometry) *Point {
+	d := dimension(g)
+	if d < 0 {
+		return nil
+	}
+
+	if d == 2 {
+		var best *Point
+		bestWidth := -1.0
+		components(g, func(c Geometry) {
+			s, ok := c.(Surface)
+			if !ok || s.IsEmpty() {
+				return
+			}
+			if pt, width := widestSection(s); pt != nil && width > bestWidth {
+				best, bestWidth = pt, width
+			}
+		})
+		if best != nil {
+			return best
+		}
+		//Only degenerate polygons: fall back to their vertices
+		d = 1
+	}
+
+	centroid := Centroid(g)
+	var best *Point
+	bestDist := math.Inf(1)
+	consider := func(pt Point) {
+		if dist := math.Hypot(pt.X-centroid.X, pt.Y-centroid.Y); dist < bestDist {
+			p := pt
+			best, bestDist = &p, dist
+		}
+	}
+
+	//Interior vertices of lines first, then line ends and points
+	if d >= 1 {
+		components(g, func(c Geometry) {
+			if c.Dimension() >= 1 {
+				c.Iterate(func(points []Point) error {
+					for i := 1; i < len(points)-1; i++ {
+						consider(points[i])
+					}
+					return nil
+				})
+			}
+		})
+	}
+	if best == nil {
+		components(g, func(c Geometry) {
+			c.Iterate(func(points []Point) error {
+				for _, pt := range points {
+					if !math.IsNaN(pt.X) && !math.IsNaN(pt.Y) {
+						consider(pt)
+					}
+				}
+				return nil
+			})
+		})
+	}
+	return best
+}
+
+//widestSection returns the middle of the widest interior section of the polygon along a horizontal
+//line crossing its envelope center, and the width of this section.
+func widestSection(s Surface) (*Point, float64) {
+	e := s.Envelope()
+	center := (e.Min.Y + e.Max.Y) / 2
+
+	//The scan line lies strictly between the vertices closest to the center, so that it never passes through a vertex
+	below, above := math.Inf(-1), math.Inf(1)
+	s.Iterate(func(ring []Point) error {
+		for _, pt := range ring {
+			if pt.Y <= center && pt.Y > below {
+				below = pt.Y
+			}
+			if pt.Y > center && pt.Y < above {
+				above = pt.Y
+			}
+		}
+		return nil
+	})
+	if math.IsInf(below, 0) || math.IsInf(above, 0) {
+		return nil, 0
+	}
+	y := (below + above) / 2
+
+	var xs []float64
+	s.Iterate(func(ring []Point) error {
+		for i := 1; i < len(ring); i++ {
+			a, b := ring[i-1], ring[i]
+			if (a.Y > y) != (b.Y > y) {
+				xs = append(xs, a.X+(y-a.Y)*(b.X-a.X)/(b.Y-a.Y))
+			}
+		}
+		return nil
+	})
+	sort.Float64s(xs)
+
+	var best *Point
+	bestWidth := -1.0
+	for i := 0; i+1 < len(xs); i += 2 {
+		if width := xs[i+1] - xs[i]; width > bestWidth {
+			best, bestWidth = &Point{X: (xs[i] + xs[i+1]) / 2, Y: y}, width
+		}
+	}
+	return best, bestWidth
+}
+
+//segmentDistance returns the distance between p and the segment [a, b]
+func segmentDistance(p, a, b Point) float64 {
+	x, y := a.X, a.Y
+	dx, dy := b.X-x, b.Y-y
+	if dx != 0 || dy != 0 {
+		t := ((p.X-x)*dx + (p.Y-y)*dy) / (dx*dx + dy*dy)
+		if t > 1 {
+			x, y = b.X, b.Y
+		} else if t > 0 {
+			x += dx * t
+			y += dy * t
+		}
+	}
+	return math.Hypot(p.X-x, p.Y-y)
+}
+
+//ringsDistance returns the signed distance from p to the rings:
+//positive if p is inside (using the even-odd rule), negative outside
+func ringsDistance(p Point, rings [][]Point) float64 {
+	inside := false
+	dist := math.Inf(1)
+	for _, ring := range rings {
+		for i := 1; i < len(ring); i++ {
+			a, b := ring[i-1], ring[i]
+			if (a.Y > p.Y) != (b.Y > p.Y) && p.X < (b.X-a.X)*(p.Y-a.Y)/(b.Y-a.Y)+a.X {
+				inside = !inside
+			}
+			dist = math.Min(dist, segmentDistance(p, a, b))
+		}
+	}
+	if inside {
+		return dist
+	}
+	return -dist
+}
+
+//polylabelCell is a square cell of the pole of inaccessibility search
+type polylabelCell struct {
+	center Point
+	half   float64 //half the cell size
+	dist   float64 //distance from the cell center to the polygon
+	max    float64 //max distance to the polygon within the cell
+}
+
+func newPolylabelCell(center Point, half float64, rings [][]Point) *polylabelCell {
+	d := ringsDistance(center, rings)
+	return &polylabelCell{
+		center: center,
+		half:   half,
+		dist:   d,
+		max:    d + half*math.Sqrt2,
+	}
+}
+
+//polylabelQueue is a priority queue of cells, by decreasing max distance
+type polylabelQueue []*polylabelCell
+
+func (q polylabelQueue) Len() int            { return len(q) }
+func (q polylabelQueue) Less(i, j int) bool  { return q[i].max > q[j].max }
+func (q polylabelQueue) Swap(i, j int)       { q[i], q[j] = q[j], q[i] }
+func (q *polylabelQueue) Push(x interface{}) { *q = append(*q, x.(*polylabelCell)) }
+func (q *polylabelQueue) Pop() interface{} {
+	old := *q
+	cell := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return cell
+}
+
+//PoleOfInaccessibility returns the most distant internal point from the polygon outline,
+//and its distance to the outline, using the polylabel algorithm.
+//The point is found within the given precision (in coordinates units).
+//Polygons of a MultiPolygon or a GeometryCollection are handled together.
+//It returns nil if the geometry has no polygon.
+func PoleOfInaccessibility(g Geometry, precision float64) (*Point, float64) {
+	var rings [][]Point
+	surfaces(g, func(s Surface) {
+		s.Iterate(func(ring []Point) error {
+			rings = append(rings, append([]Point(nil), ring...))
+			return nil
+		})
+	})
+	if len(rings) == 0 {
+		return nil, 0
+	}
+
+	e := NewEnvelope()
+	for _, ring := range rings {
+		e.Extend(LineString(ring).Envelope())
+	}
+	width, height := e.Max.X-e.Min.X, e.Max.Y-e.Min.Y
+	cellSize := math.Min(width, height)
+	if cellSize == 0 || math.IsInf(cellSize, 0) || math.IsNaN(cellSize) {
+		if math.IsInf(e.Min.X, 0) {
+			return nil, 0
+		}
+		return &Point{X: e.Min.X, Y: e.Min.Y}, 0
+	}
+	if precision <= 0 {
+		precision = cellSize * 1e-6
+	}
+
+	//Cover the envelope with square cells
+	half := cellSize / 2
+	q := &polylabelQueue{}
+	for x := e.Min.X; x < e.Max.X; x += cellSize {
+		for y := e.Min.Y; y < e.Max.Y; y += cellSize {
+			heap.Push(q, newPolylabelCell(Point{X: x + half, Y: y + half}, half, rings))
+		}
+	}
+
+	//First guesses: the centroid and the envelope center
+	best := newPolylabelCell(Point{X: e.Min.X + width/2, Y: e.Min.Y + height/2}, 0, rings)
+	if c := Centroid(g); c != nil {
+		if cell := newPolylabelCell(*c, 0, rings); cell.dist > best.dist {
+			best = cell
+		}
+	}
+
+	for q.Len() > 0 {
+		cell := heap.Pop(q).(*polylabelCell)
+		if cell.dist > best.dist {
+			best = cell
+		}
+		//Stop exploring the cell if it can not contain a better solution
+		if cell.max-best.dist <= precision {
+			continue
+		}
+		h := cell.half / 2
+		heap.Push(q, newPolylabelCell(Point{X: cell.center.X - h, Y: cell.center.Y - h}, h, rings))
+		heap.Push(q, newPolylabelCell(Point{X: cell.center.X + h, Y: cell.center.Y - h}, h, rings))
+		heap.Push(q, newPolylabelCell(Point{X: cell.center.X - h, Y: cell.center.Y + h}, h, rings))
+		heap.Push(q, newPolylabelCell(Point{X: cell.center.X + h, Y: cell.center.Y + h}, h, rings))
+	}
+
+	pt := best.center
+	return &pt, best.dist
+}
@@ -0,0 +1,180 @@
+// Copyright 2015 Simon HEGE. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package geom
+
+import (
+	"math"
+	"sort"
+)
+
+//projectOnSegment returns the point of the segment [a, b] closest to p,
+//and its position along the segment, from 0 at a to 1 at b
+func projectOnSegment(p, a, b Point) (Point, float64) {
+	dx, dy := b.X-a.X, b.Y-a.Y
+	if dx == 0 && dy == 0 {
+		return a, 0
+	}
+	t := ((p.X-a.X)*dx + (p.Y-a.Y)*dy) / (dx*dx + dy*dy)
+	switch {
+	case t <= 0:
+		return a, 0
+	case t >= 1:
+		return b, 1
+	}
+	return Point{X: a.X + dx*t, Y: a.Y + dy*t}, t
+}
+
+//segmentsNearest returns the closest points of the segments [a, b] and [c, d], and their distance.
+//Points are handled as segments whose end points are equal.
+func segmentsNearest(a, b, c, d Point) (Point, Point, float64) {
+	if a != b && c != d && segmentsIntersect(a, b, c, d) {
+		if points := segmentIntersection(a, b, c, d); len(points) > 0 {
+			return points[0], points[0], 0
+		}
+	}
+
+	p, q, dist := a, c, math.Inf(1)
+	try := func(p1, p2 Point) {
+		if dd := math.Hypot(p2.X-p1.X, p2.Y-p1.Y); dd < dist {
+			p, q, dist = p1, p2, dd
+		}
+	}
+	for _, pt := range []Point{a, b} {
+		proj, _ := projectOnSegment(pt, c, d)
+		try(pt, proj)
+	}
+	for _, pt := range []Point{c, d} {
+		proj, _ := projectOnSegment(pt, a, b)
+		try(proj, pt)
+	}
+	return p, q, dist
+}
+
+//facets returns the segments of the points, lines and polygon rings, points being degenerate segments
+func (p *parts) facets() [][2]Point {
+	var facets [][2]Point
+	for _, pt := range p.points {
+		facets = append(facets, [2]Point{pt, pt})
+	}
+	add := func(line []Point) {
+		if len(line) == 1 {
+			facets = append(facets, [2]Point{line[0], line[0]})
+		}
+		for i := 1; i < len(line); i++ {
+			facets = append(facets, [2]Point{line[i-1], line[i]})
+		}
+	}
+	for _, line := range p.lines {
+		add(line)
+	}
+	for _, polygon := range p.polygons {
+		for _, ring := range polygon {
+			add(ring)
+		}
+	}
+	return facets
+}
+
+//first returns a point of each component
+func (p *parts) first() []Point {
+	points := append([]Point(nil), p.points...)
+	for _, line := range p.lines {
+		points = append(points, line[0])
+	}
+	for _, polygon := range p.polygons {
+		points = append(points, polygon[0][0])
+	}
+	return points
+}
+
+//nearestPoints returns the closest points of a and b, and their distance (infinite if a or b is empty)
+func nearestPoints(a, b Geometry) (Point, Point, float64) {
+	pa, pb := newParts(a), newParts(b)
+
+	//Component of one geometry inside a polygon of the other one
+	for _, polygon := range pb.polygons {
+		for _, pt := range pa.first() {
+			if locateInRings(pt, polygon) != Exterior {
+				return pt, pt, 0
+			}
+		}
+	}
+	for _, polygon := range pa.polygons {
+		for _, pt := range pb.first() {
+			if locateInRings(pt, polygon) != Exterior {
+				return pt, pt, 0
+			}
+		}
+	}
+
+	//Closest facets, pruned along the X axis by the best distance found so far
+	fa, fb := pa.facets(), pb.facets()
+	minX := func(f [2]Point) float64 {
+		return math.Min(f[0].X, f[1].X)
+	}
+	sort.Slice(fb, func(i, j int) bool {
+		return minX(fb[i]) < minX(fb[j])
+	})
+	var p, q Point
+	dist := math.Inf(1)
+	for _, f := range fa {
+		fMinX, fMaxX := minX(f), math.Max(f[0].X, f[1].X)
+		for _, g := range fb {
+			if minX(g) > fMaxX+dist {
+				break
+			}
+			if math.Max(g[0].X, g[1].X) < fMinX-dist {
+				continue
+			}
+			if p1, p2, d := segmentsNearest(f[0], f[1], g[0], g[1]); d < dist {
+				p, q, dist = p1, p2, d
+				if dist == 0 {
+					return p, q, 0
+				}
+			}
+		}
+	}
+	return p, q, dist
+}
+
+//Distance returns the minimum planar distance between a and b, 0 if they intersect (including when one is inside
+//a polygon of the other one). All the geometry types and dimension variants are accepted, Z and M values are ignored.
+//Geometry collections are handled recursively. The distance is infinite if a or b is empty.
+func Distance(a, b Geometry) float64 {
+	_, _, dist := nearestPoints(a, b)
+	return dist
+}
+
+//NearestPoints returns a point of a and a point of b whose distance is Distance(a, b).
+//When a and b intersect, both points are the same intersection point.
+//ok is false if a or b is empty.
+func NearestPoints(a, b Geometry) (pa, pb Point, ok bool) {
+	pa, pb, dist := nearestPoints(a, b)
+	return pa, pb, !math.IsInf(dist, 1)
+}
+
+//ClosestPoint returns the point of line closest to pt, and its distance along the line from the start point.
+//If several points are at the same distance, the first one along the line is returned.
+//For an empty line, the zero point and a distance of 0 are returned.
+func ClosestPoint(line Curve, pt Point) (Point, float64) {
+	points := pointsOf(line)
+	if len(points) == 0 {
+		return Point{}, 0
+	}
+
+	closest, along := points[0], 0.0
+	best := math.Hypot(pt.X-closest.X, pt.Y-closest.Y)
+	length := 0.0
+	for i := 1; i < len(points); i++ {
+		a, b := points[i-1], points[i]
+		segmentLength := math.Hypot(b.X-a.X, b.Y-a.Y)
+		proj, t := projectOnSegment(pt, a, b)
+		if d := math.Hypot(pt.X-proj.X, pt.Y-proj.Y); d < best {
+			closest, along, best = proj, length+t*segmentLength, d
+		}
+		length += segmentLength
+	}
+	return closest, along
+}
@@ -0,0 +1,471 @@
+// Copyright 2015 Simon HEGE. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+/*
+Package csvgeom implements reading and writing of GeoJSON features as CSV files.
+
+The geometry of each row is either stored as WKT in a single column, or as a point
+split in a longitude and a latitude column, with optional Z and M columns.
+All other columns are stored as feature properties. Property values are read as strings.
+
+As GeoJSON has no M coordinate, the third coordinate of a point always comes from the Z column,
+and the M value is kept as a property named after the M column. It is written back to the M column,
+unless the geometry has its own M value.
+*/
+package csvgeom
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/xeonx/geom"
+	"github.com/xeonx/geom/encoding/geojson"
+	"github.com/xeonx/geom/encoding/wkt"
+)
+
+//Columns holds the names of the columns containing the feature identifier and geometry.
+//Either WKT or both Lon and Lat must be set.
+type Columns struct {
+	//ID is the column holding the feature identifier, if any
+	ID string
+	//WKT is the column holding the geometry as WKT
+	WKT string
+	//Lon and Lat are the columns holding the point coordinates
+	Lon, Lat string
+	//Z and M are the optional columns holding the Z and M coordinates of the point
+	Z, M string
+}
+
+//DetectColumns guesses the geometry columns from the header names (case insensitive).
+//It looks for a WKT column (wkt, geometry, geom, the_geom), then for longitude
+//(lon, lng, long, longitude, x) and latitude (lat, latitude, y) columns.
+func DetectColumns(header []string) (Columns, error) {
+	find := func(names ...string) string {
+		for _, name := range names {
+			for _, h := range header {
+				if strings.EqualFold(strings.TrimSpace(h), name) {
+					return h
+				}
+			}
+		}
+		return ""
+	}
+
+	var c Columns
+	if c.WKT = find("wkt", "geometry", "geom", "the_geom"); c.WKT != "" {
+		return c, nil
+	}
+	c.Lon = find("lon", "lng", "long", "longitude", "x")
+	c.Lat = find("lat", "latitude", "y")
+	if c.Lon == "" || c.Lat == "" {
+		return c, errors.New("Unable to detect the geometry columns")
+	}
+	c.Z = find("z", "alt", "altitude", "elevation")
+	c.M = find("m")
+	return c, nil
+}
+
+func (c *Columns) validate() error {
+	if c.WKT == "" && (c.Lon == "" || c.Lat == "") {
+		return errors.New("Missing geometry columns: either WKT or Lon and Lat are required")
+	}
+	if c.WKT != "" && (c.Lon != "" || c.Lat != "" || c.Z != "" || c.M != "") {
+		return errors.New("Invalid geometry columns: WKT can not be combined with Lon, Lat, Z or M")
+	}
+	return nil
+}
+
+//names returns the names of the geometry and identifier columns
+func (c *Columns) names() []string {
+	var names []string
+	for _, name := range []string{c.ID, c.WKT, c.Lon, c.Lat, c.Z, c.M} {
+		if name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+//A RowError is returned for a row that can not be converted into a feature
+type RowError struct {
+	Line   int    //Line of the row in the input (1-based)
+	Column string //Name of the faulty column
+	Err    error
+}
+
+func (e *RowError) Error() string {
+	return fmt.Sprintf("line %d, column %s: %v", e.Line, e.Column, e.Err)
+}
+
+//A Reader reads features from a CSV file with a header row
+type Reader struct {
+	r *csv.Reader
+
+	//Columns holds the geometry columns. If empty, they are detected from the header.
+	Columns Columns
+	//Comma is the field delimiter. It is set to ',' by NewReader.
+	Comma rune
+
+	header []string
+	index  map[string]int
+}
+
+//NewReader returns a new reader that reads from r
+func NewReader(r io.Reader) *Reader {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	return &Reader{
+		r:     cr,
+		Comma: ',',
+	}
+}
+
+//readHeader reads the header row and resolves the columns
+func (r *Reader) readHeader() error {
+	r.r.Comma = r.Comma
+	header, err := r.r.Read()
+	if err != nil {
+		return err
+	}
+	r.header = header
+	r.index = make(map[string]int, len(header))
+	for i, name := range header {
+		r.index[name] = i
+	}
+
+	if r.Columns == (Columns{}) {
+		if r.Columns, err = DetectColumns(header); err != nil {
+			return err
+		}
+	}
+	if err := r.Columns.validate(); err != nil {
+		return err
+	}
+	for _, name := range r.Columns.names() {
+		if _, ok := r.index[name]; !ok {
+			return fmt.Errorf("Missing column in CSV header: %s", name)
+		}
+	}
+	return nil
+}
+
+//Header returns the header row, once the first feature has been read
+func (r *Reader) Header() []string {
+	return r.header
+}
+
+//Read reads the next feature. It returns io.EOF at the end of the input.
+//Errors on a given row are returned as *RowError; reading may continue with the next row.
+//Rows with empty geometry cells produce features without geometry.
+func (r *Reader) Read() (*geojson.Feature, error) {
+	if r.header == nil {
+		if err := r.readHeader(); err != nil {
+			return nil, err
+		}
+	}
+
+	record, err := r.r.Read()
+	if err != nil {
+		return nil, err
+	}
+	line, _ := r.r.FieldPos(0)
+
+	value := func(name string) string {
+		if i := r.index[name]; i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	f := &geojson.Feature{
+		Type:       "Feature",
+		Properties: make(map[string]interface{}),
+	}
+	for i, name := range r.header {
+		if r.isGeometryColumn(name) {
+			continue
+		}
+		if i < len(record) {
+			f.Properties[name] = record[i]
+		} else {
+			f.Properties[name] = ""
+		}
+	}
+	if r.Columns.ID != "" {
+		f.ID = value(r.Columns.ID)
+	}
+	if r.Columns.M != "" {
+		f.Properties[r.Columns.M] = value(r.Columns.M)
+	}
+
+	var g geom.Geometry
+	column := r.Columns.WKT
+	if r.Columns.WKT != "" {
+		if s := value(r.Columns.WKT); s != "" {
+			if g, err = wkt.Parse(s); err != nil {
+				return nil, &RowError{Line: line, Column: r.Columns.WKT, Err: err}
+			}
+		}
+	} else {
+		column = r.Columns.Lon
+		g, err = r.point(value, line)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if g != nil {
+		gj, err := geojson.ToGeoJSON(g)
+		if err != nil {
+			return nil, &RowError{Line: line, Column: column, Err: err}
+		}
+		f.Geometry = *gj
+	}
+	return f, nil
+}
+
+//point builds a point from the coordinates columns, the M value being only checked.
+//It returns nil if lon and lat are empty.
+func (r *Reader) point(value func(name string) string, line int) (geom.Geometry, error) {
+	if value(r.Columns.Lon) == "" && value(r.Columns.Lat) == "" {
+		return nil, nil
+	}
+
+	parse := func(name string) (float64, error) {
+		v, err := strconv.ParseFloat(value(name), 64)
+		if err != nil {
+			return 0, &RowError{Line: line, Column: name, Err: fmt.Errorf("Invalid coordinate '%s'", value(name))}
+		}
+		return v, nil
+	}
+
+	var pt geom.PointZ
+	var err error
+	if pt.X, err = parse(r.Columns.Lon); err != nil {
+		return nil, err
+	}
+	if pt.Y, err = parse(r.Columns.Lat); err != nil {
+		return nil, err
+	}
+	if r.Columns.M != "" && value(r.Columns.M) != "" {
+		if _, err = parse(r.Columns.M); err != nil {
+			return nil, err
+		}
+	}
+	if r.Columns.Z != "" && value(r.Columns.Z) != "" {
+		if pt.Z, err = parse(r.Columns.Z); err != nil {
+			return nil, err
+		}
+		return &pt, nil
+	}
+	return &pt.Point, nil
+}
+
+func (r *Reader) isGeometryColumn(name string) bool {
+	for _, n := range r.Columns.names() {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+//ReadAll reads all the remaining features.
+//It stops at the first error, which is returned with the features read so far.
+func (r *Reader) ReadAll() (*geojson.FeatureCollection, error) {
+	c := &geojson.FeatureCollection{
+		Type: "FeatureCollection",
+	}
+	for {
+		f, err := r.Read()
+		if err == io.EOF {
+			return c, nil
+		}
+		if err != nil {
+			return c, err
+		}
+		c.Features = append(c.Features, f)
+	}
+}
+
+//A Writer writes features to a CSV file, starting with a header row
+type Writer struct {
+	w *csv.Writer
+
+	//Columns holds the geometry columns. Either WKT or Lon and Lat must be set.
+	Columns Columns
+	//Properties is the ordered list of property columns.
+	//If empty, the properties of the first written feature are used, sorted by name.
+	Properties []string
+
+	headerWritten bool
+}
+
+//NewWriter returns a new writer writing to w
+func NewWriter(w io.Writer, columns Columns) *Writer {
+	return &Writer{
+		w:       csv.NewWriter(w),
+		Columns: columns,
+	}
+}
+
+func (w *Writer) writeHeader(f *geojson.Feature) error {
+	if err := w.Columns.validate(); err != nil {
+		return err
+	}
+	if len(w.Properties) == 0 && f != nil {
+		geometryColumns := w.Columns.names()
+	properties:
+		for name := range f.Properties {
+			for _, n := range geometryColumns {
+				if n == name {
+					continue properties
+				}
+			}
+			w.Properties = append(w.Properties, name)
+		}
+		sort.Strings(w.Properties)
+	}
+
+	header := append([]string{}, w.Properties...)
+	header = append(header, w.Columns.names()...)
+	w.headerWritten = true
+	return w.w.Write(header)
+}
+
+//Write writes a feature as a CSV row.
+//With Lon and Lat columns, only point geometries are supported.
+func (w *Writer) Write(f *geojson.Feature) error {
+	if !w.headerWritten {
+		if err := w.writeHeader(f); err != nil {
+			return err
+		}
+	}
+
+	record := make([]string, 0, len(w.Properties)+6)
+	for _, name := range w.Properties {
+		s, err := formatValue(f.Properties[name])
+		if err != nil {
+			return err
+		}
+		record = append(record, s)
+	}
+	if w.Columns.ID != "" {
+		record = append(record, f.ID)
+	}
+
+	var g geom.Geometry
+	if f.Geometry.Type != "" {
+		var err error
+		if g, err = geojson.FromGeoJSON(f.Geometry); err != nil {
+			return err
+		}
+	}
+
+	if w.Columns.WKT != "" {
+		s := ""
+		if g != nil {
+			var err error
+			if s, err = wkt.Format(g); err != nil {
+				return err
+			}
+		}
+		record = append(record, s)
+	} else {
+		coords, err := w.coordinates(g, f)
+		if err != nil {
+			return err
+		}
+		record = append(record, coords...)
+	}
+
+	return w.w.Write(record)
+}
+
+//coordinates returns the values of the Lon, Lat, Z and M columns.
+//Without M value in the geometry, M is taken from the property named after the M column.
+func (w *Writer) coordinates(g geom.Geometry, f *geojson.Feature) ([]string, error) {
+	var x, y, z, m string
+	format := func(v float64) string {
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+
+	switch g := g.(type) {
+	case nil:
+	case *geom.Point:
+		x, y = format(g.X), format(g.Y)
+	case *geom.PointZ:
+		x, y, z = format(g.X), format(g.Y), format(g.Z)
+	case *geom.PointM:
+		x, y, m = format(g.X), format(g.Y), format(g.M)
+	case *geom.PointZM:
+		x, y, z, m = format(g.X), format(g.Y), format(g.Z), format(g.M)
+	default:
+		return nil, fmt.Errorf("Unsupported geometry type for Lon/Lat columns: %s", reflect.TypeOf(g).String())
+	}
+
+	if _, hasM := g.(geom.GeometryM); !hasM && w.Columns.M != "" {
+		var err error
+		if m, err = formatValue(f.Properties[w.Columns.M]); err != nil {
+			return nil, err
+		}
+	}
+
+	values := []string{x, y}
+	if w.Columns.Z != "" {
+		values = append(values, z)
+	}
+	if w.Columns.M != "" {
+		values = append(values, m)
+	}
+	return values, nil
+}
+
+//formatValue formats a property value. Values other than strings, numbers and booleans are written as JSON.
+func formatValue(v interface{}) (string, error) {
+	switch v := v.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case int:
+		return strconv.Itoa(v), nil
+	case int64:
+		return strconv.FormatInt(v, 10), nil
+	}
+	b, err := json.Marshal(v)
+	return string(b), err
+}
+
+//WriteAll writes all the features of c and flushes the writer
+func (w *Writer) WriteAll(c *geojson.FeatureCollection) error {
+	for _, f := range c.Features {
+		if err := w.Write(f); err != nil {
+			return err
+		}
+	}
+	return w.Flush()
+}
+
+//Flush writes any buffered data to the underlying writer
+func (w *Writer) Flush() error {
+	if !w.headerWritten {
+		if err := w.writeHeader(nil); err != nil {
+			return err
+		}
+	}
+	w.w.Flush()
+	return w.w.Error()
+}
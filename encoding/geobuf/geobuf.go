@@ -0,0 +1,798 @@
+// Copyright 2015 Simon HEGE. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+/*
+Package geobuf implements encoding and decoding of Geobuf objects.
+
+Geobuf is a compact binary encoding of GeoJSON based on protocol buffers. Coordinates are stored
+as delta-encoded integers, after multiplication by 10^precision. The format is described at
+https://github.com/mapbox/geobuf
+
+Features and feature collections are those of the geojson package. As in the geojson package,
+coordinates with 3 values are decoded as X/Y/Z and M geometries are encoded with M as the third
+coordinate.
+*/
+package geobuf
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+
+	"github.com/xeonx/geom"
+	"github.com/xeonx/geom/encoding/geojson"
+)
+
+//DefaultPrecision is the number of decimal digits kept on coordinates by default
+const DefaultPrecision = 6
+
+//Geometry types as defined in the Geobuf schema
+var geometryTypes = map[string]uint64{
+	"Point":              0,
+	"MultiPoint":         1,
+	"LineString":         2,
+	"MultiLineString":    3,
+	"Polygon":            4,
+	"MultiPolygon":       5,
+	"GeometryCollection": 6,
+}
+
+var geometryTypeNames = []string{"Point", "MultiPoint", "LineString", "MultiLineString", "Polygon", "MultiPolygon", "GeometryCollection"}
+
+//An Encoder encodes geometries and features as Geobuf messages
+type Encoder struct {
+	//Precision is the number of decimal digits kept on coordinates
+	Precision int
+	//Dimensions is the number of values stored for each coordinate.
+	//If 0, it is the largest dimension of the encoded coordinates.
+	//Coordinates with less values are padded with 0.
+	Dimensions int
+}
+
+//NewEncoder returns an encoder using the default precision and detecting dimensions
+func NewEncoder() *Encoder {
+	return &Encoder{
+		Precision: DefaultPrecision,
+	}
+}
+
+//encoding holds the state of a single encoding
+type encoding struct {
+	dim  int
+	e    float64
+	keys map[string]uint64
+}
+
+//EncodeGeometry returns the Geobuf representation of g
+func (enc *Encoder) EncodeGeometry(g geom.Geometry) ([]byte, error) {
+	gj, err := geojson.ToGeoJSON(g)
+	if err != nil {
+		return nil, err
+	}
+
+	s, w := enc.start(nil, []*geojson.Geometry{gj})
+	var m pbfWriter
+	if err := s.writeGeometry(&m, gj); err != nil {
+		return nil, err
+	}
+	w.bytesField(6, m.buf)
+	return w.buf, nil
+}
+
+//EncodeFeature returns the Geobuf representation of f
+func (enc *Encoder) EncodeFeature(f *geojson.Feature) ([]byte, error) {
+	geometries, err := normalize([]*geojson.Feature{f})
+	if err != nil {
+		return nil, err
+	}
+
+	s, w := enc.start([]*geojson.Feature{f}, geometries)
+	m, err := s.feature(f, geometries[0])
+	if err != nil {
+		return nil, err
+	}
+	w.bytesField(5, m.buf)
+	return w.buf, nil
+}
+
+//EncodeFeatureCollection returns the Geobuf representation of c
+func (enc *Encoder) EncodeFeatureCollection(c *geojson.FeatureCollection) ([]byte, error) {
+	geometries, err := normalize(c.Features)
+	if err != nil {
+		return nil, err
+	}
+
+	s, w := enc.start(c.Features, geometries)
+	var m pbfWriter
+	for i, f := range c.Features {
+		fm, err := s.feature(f, geometries[i])
+		if err != nil {
+			return nil, err
+		}
+		m.bytesField(1, fm.buf)
+	}
+	w.bytesField(4, m.buf)
+	return w.buf, nil
+}
+
+//normalize returns the geometries of the features, with typed coordinates
+func normalize(features []*geojson.Feature) ([]*geojson.Geometry, error) {
+	geometries := make([]*geojson.Geometry, len(features))
+	for i, f := range features {
+		if f.Geometry.Type == "" {
+			continue
+		}
+		g, err := geojson.FromGeoJSON(f.Geometry)
+		if err != nil {
+			return nil, err
+		}
+		if geometries[i], err = geojson.ToGeoJSON(g); err != nil {
+			return nil, err
+		}
+	}
+	return geometries, nil
+}
+
+//start writes the header of the Data message: keys, dimensions and precision
+func (enc *Encoder) start(features []*geojson.Feature, geometries []*geojson.Geometry) (*encoding, *pbfWriter) {
+	s := &encoding{
+		dim:  enc.Dimensions,
+		e:    math.Pow10(enc.Precision),
+		keys: make(map[string]uint64),
+	}
+	if s.dim == 0 {
+		s.dim = 2
+		for _, g := range geometries {
+			if d := dimensions(g); d > s.dim {
+				s.dim = d
+			}
+		}
+	}
+
+	var w pbfWriter
+	for _, f := range features {
+		for _, key := range sortedKeys(f.Properties) {
+			if _, ok := s.keys[key]; !ok {
+				s.keys[key] = uint64(len(s.keys))
+				w.stringField(1, key)
+			}
+		}
+	}
+	if s.dim != 2 {
+		w.varintField(2, uint64(s.dim))
+	}
+	if enc.Precision != DefaultPrecision {
+		w.varintField(3, uint64(enc.Precision))
+	}
+	return s, &w
+}
+
+func sortedKeys(properties map[string]interface{}) []string {
+	keys := make([]string, 0, len(properties))
+	for key := range properties {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+//dimensions returns the largest dimension of the coordinates of g
+func dimensions(g *geojson.Geometry) int {
+	if g == nil {
+		return 0
+	}
+	dim := 0
+	switch c := g.Coordinates.(type) {
+	case []float64:
+		dim = len(c)
+	case [][]float64:
+		for _, pt := range c {
+			if len(pt) > dim {
+				dim = len(pt)
+			}
+		}
+	case [][][]float64:
+		for _, line := range c {
+			for _, pt := range line {
+				if len(pt) > dim {
+					dim = len(pt)
+				}
+			}
+		}
+	case [][][][]float64:
+		for _, polygon := range c {
+			for _, line := range polygon {
+				for _, pt := range line {
+					if len(pt) > dim {
+						dim = len(pt)
+					}
+				}
+			}
+		}
+	}
+	for _, child := range g.Geometries {
+		if d := dimensions(child); d > dim {
+			dim = d
+		}
+	}
+	return dim
+}
+
+func (s *encoding) feature(f *geojson.Feature, g *geojson.Geometry) (*pbfWriter, error) {
+	var m pbfWriter
+	if g != nil {
+		var gm pbfWriter
+		if err := s.writeGeometry(&gm, g); err != nil {
+			return nil, err
+		}
+		m.bytesField(1, gm.buf)
+	}
+	if f.ID != "" {
+		m.stringField(11, f.ID)
+	}
+
+	var properties []uint64
+	for _, key := range sortedKeys(f.Properties) {
+		v, err := writeValue(f.Properties[key])
+		if err != nil {
+			return nil, err
+		}
+		m.bytesField(13, v.buf)
+		properties = append(properties, s.keys[key], uint64(len(properties)/2))
+	}
+	m.packedVarintField(14, properties)
+	return &m, nil
+}
+
+//writeValue returns the Value message holding v
+func writeValue(v interface{}) (*pbfWriter, error) {
+	var m pbfWriter
+	switch v := v.(type) {
+	case nil:
+	case string:
+		m.stringField(1, v)
+	case bool:
+		b := uint64(0)
+		if v {
+			b = 1
+		}
+		m.varintField(5, b)
+	case float64:
+		writeNumber(&m, v)
+	case float32:
+		writeNumber(&m, float64(v))
+	case int:
+		writeInt(&m, int64(v))
+	case int32:
+		writeInt(&m, int64(v))
+	case int64:
+		writeInt(&m, v)
+	case uint32:
+		m.varintField(3, uint64(v))
+	case uint64:
+		m.varintField(3, v)
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			writeInt(&m, i)
+		} else if f, err := v.Float64(); err == nil {
+			writeNumber(&m, f)
+		} else {
+			return nil, err
+		}
+	default:
+		b, err := json.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		m.bytesField(6, b)
+	}
+	return &m, nil
+}
+
+func writeNumber(m *pbfWriter, v float64) {
+	if v == math.Trunc(v) && math.Abs(v) < 1<<53 {
+		writeInt(m, int64(v))
+		return
+	}
+	m.doubleField(2, v)
+}
+
+func writeInt(m *pbfWriter, v int64) {
+	if v >= 0 {
+		m.varintField(3, uint64(v))
+	} else {
+		m.varintField(4, uint64(-v))
+	}
+}
+
+func (s *encoding) writeGeometry(w *pbfWriter, g *geojson.Geometry) error {
+	t, ok := geometryTypes[g.Type]
+	if !ok {
+		return errors.New("Unsupported geometry type: " + g.Type)
+	}
+	w.varintField(1, t)
+
+	var lengths []uint64
+	var coords []int64
+	switch c := g.Coordinates.(type) {
+	case []float64:
+		coords = s.appendPoint(coords, c, nil)
+	case [][]float64:
+		coords = s.appendLine(coords, c, false)
+	case [][][]float64:
+		closed := g.Type == "Polygon"
+		if len(c) != 1 {
+			for _, line := range c {
+				lengths = append(lengths, uint64(lineLength(line, closed)))
+			}
+		}
+		for _, line := range c {
+			coords = s.appendLine(coords, line, closed)
+		}
+	case [][][][]float64:
+		if len(c) != 1 || len(c[0]) != 1 {
+			lengths = append(lengths, uint64(len(c)))
+			for _, polygon := range c {
+				lengths = append(lengths, uint64(len(polygon)))
+				for _, ring := range polygon {
+					lengths = append(lengths, uint64(lineLength(ring, true)))
+				}
+			}
+		}
+		for _, polygon := range c {
+			for _, ring := range polygon {
+				coords = s.appendLine(coords, ring, true)
+			}
+		}
+	case nil:
+	default:
+		return fmt.Errorf("Unsupported GeoJSON coordinates type: %T", c)
+	}
+	w.packedVarintField(2, lengths)
+	w.packedSVarintField(3, coords)
+
+	for _, child := range g.Geometries {
+		var m pbfWriter
+		if err := s.writeGeometry(&m, child); err != nil {
+			return err
+		}
+		w.bytesField(4, m.buf)
+	}
+	return nil
+}
+
+//lineLength returns the number of encoded points of a line, the closing point of rings being omitted
+func lineLength(line [][]float64, closed bool) int {
+	if closed && len(line) > 0 {
+		return len(line) - 1
+	}
+	return len(line)
+}
+
+//appendPoint appends the coordinates of pt, delta-encoded against sum if not nil
+func (s *encoding) appendPoint(coords []int64, pt []float64, sum []int64) []int64 {
+	for j := 0; j < s.dim; j++ {
+		var v int64
+		if j < len(pt) {
+			v = int64(math.Round(pt[j] * s.e))
+		}
+		if sum != nil {
+			v, sum[j] = v-sum[j], v
+		}
+		coords = append(coords, v)
+	}
+	return coords
+}
+
+func (s *encoding) appendLine(coords []int64, line [][]float64, closed bool) []int64 {
+	sum := make([]int64, s.dim)
+	for _, pt := range line[:lineLength(line, closed)] {
+		coords = s.appendPoint(coords, pt, sum)
+	}
+	return coords
+}
+
+//data is a decoded Data message
+type data struct {
+	keys       []string
+	dim        int
+	e          float64
+	collection *geojson.FeatureCollection
+	feature    *geojson.Feature
+	geometry   *geojson.Geometry
+}
+
+func decode(b []byte) (*data, error) {
+	d := &data{dim: 2}
+	precision := DefaultPrecision
+	var collection, feature, geometry []byte
+
+	r := pbfReader{buf: b}
+	for r.more() {
+		field, wireType, err := r.next()
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case field == 1 && wireType == wireBytes:
+			key, err := r.bytes()
+			if err != nil {
+				return nil, err
+			}
+			d.keys = append(d.keys, string(key))
+		case field == 2 && wireType == wireVarint:
+			v, err := r.varint()
+			if err != nil {
+				return nil, err
+			}
+			d.dim = int(v)
+		case field == 3 && wireType == wireVarint:
+			v, err := r.varint()
+			if err != nil {
+				return nil, err
+			}
+			precision = int(v)
+		case field == 4 && wireType == wireBytes:
+			collection, err = r.bytes()
+		case field == 5 && wireType == wireBytes:
+			feature, err = r.bytes()
+		case field == 6 && wireType == wireBytes:
+			geometry, err = r.bytes()
+		default:
+			err = r.skip(wireType)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	if d.dim < 1 {
+		return nil, fmt.Errorf("Invalid Geobuf dimensions: %d", d.dim)
+	}
+	d.e = math.Pow10(precision)
+
+	var err error
+	switch {
+	case collection != nil:
+		d.collection, err = d.readFeatureCollection(collection)
+	case feature != nil:
+		d.feature, err = d.readFeature(feature)
+	case geometry != nil:
+		d.geometry, err = d.readGeometry(geometry)
+	default:
+		err = errors.New("Invalid Geobuf data: no content")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+//DecodeGeometry decodes a Geobuf message holding a bare geometry
+func DecodeGeometry(b []byte) (geom.Geometry, error) {
+	d, err := decode(b)
+	if err != nil {
+		return nil, err
+	}
+	if d.geometry == nil {
+		return nil, errors.New("Geobuf data does not hold a geometry")
+	}
+	return geojson.FromGeoJSON(*d.geometry)
+}
+
+//DecodeFeature decodes a Geobuf message holding a feature
+func DecodeFeature(b []byte) (*geojson.Feature, error) {
+	d, err := decode(b)
+	if err != nil {
+		return nil, err
+	}
+	if d.feature == nil {
+		return nil, errors.New("Geobuf data does not hold a feature")
+	}
+	return d.feature, nil
+}
+
+//DecodeFeatureCollection decodes a Geobuf message holding a feature collection
+func DecodeFeatureCollection(b []byte) (*geojson.FeatureCollection, error) {
+	d, err := decode(b)
+	if err != nil {
+		return nil, err
+	}
+	if d.collection == nil {
+		return nil, errors.New("Geobuf data does not hold a feature collection")
+	}
+	return d.collection, nil
+}
+
+func (d *data) readFeatureCollection(b []byte) (*geojson.FeatureCollection, error) {
+	c := &geojson.FeatureCollection{
+		Type:     "FeatureCollection",
+		Features: []*geojson.Feature{},
+	}
+
+	r := pbfReader{buf: b}
+	for r.more() {
+		field, wireType, err := r.next()
+		if err != nil {
+			return nil, err
+		}
+		if field == 1 && wireType == wireBytes {
+			m, err := r.bytes()
+			if err != nil {
+				return nil, err
+			}
+			f, err := d.readFeature(m)
+			if err != nil {
+				return nil, err
+			}
+			c.Features = append(c.Features, f)
+		} else if err := r.skip(wireType); err != nil {
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+func (d *data) readFeature(b []byte) (*geojson.Feature, error) {
+	f := &geojson.Feature{
+		Type:       "Feature",
+		Properties: make(map[string]interface{}),
+	}
+	var values []interface{}
+	var properties []uint64
+
+	r := pbfReader{buf: b}
+	for r.more() {
+		field, wireType, err := r.next()
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case field == 1 && wireType == wireBytes:
+			var m []byte
+			if m, err = r.bytes(); err == nil {
+				var g *geojson.Geometry
+				if g, err = d.readGeometry(m); err == nil {
+					f.Geometry = *g
+				}
+			}
+		case field == 11 && wireType == wireBytes:
+			var id []byte
+			id, err = r.bytes()
+			f.ID = string(id)
+		case field == 12 && wireType == wireVarint:
+			var id uint64
+			id, err = r.varint()
+			f.ID = strconv.FormatInt(unzigzag(id), 10)
+		case field == 13 && wireType == wireBytes:
+			var m []byte
+			if m, err = r.bytes(); err == nil {
+				var v interface{}
+				v, err = readValue(m)
+				values = append(values, v)
+			}
+		case field == 14:
+			properties, err = r.varints(wireType, properties)
+		default:
+			err = r.skip(wireType)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if len(properties)%2 != 0 {
+		return nil, errors.New("Invalid Geobuf feature properties")
+	}
+	for i := 0; i < len(properties); i += 2 {
+		k, v := properties[i], properties[i+1]
+		if k >= uint64(len(d.keys)) || v >= uint64(len(values)) {
+			return nil, errors.New("Invalid Geobuf feature properties")
+		}
+		f.Properties[d.keys[k]] = values[v]
+	}
+	return f, nil
+}
+
+//readValue decodes a Value message. Numbers are returned as float64, as encoding/json does.
+func readValue(b []byte) (interface{}, error) {
+	var v interface{}
+
+	r := pbfReader{buf: b}
+	for r.more() {
+		field, wireType, err := r.next()
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case (field == 1 || field == 6) && wireType == wireBytes:
+			var s []byte
+			if s, err = r.bytes(); err == nil {
+				v = string(s)
+				if field == 6 {
+					err = json.Unmarshal(s, &v)
+				}
+			}
+		case field == 2 && wireType == wireFixed64:
+			v, err = r.double()
+		case field >= 3 && field <= 5 && wireType == wireVarint:
+			var i uint64
+			i, err = r.varint()
+			switch field {
+			case 3:
+				v = float64(i)
+			case 4:
+				v = -float64(i)
+			case 5:
+				v = i != 0
+			}
+		default:
+			err = r.skip(wireType)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return v, nil
+}
+
+func (d *data) readGeometry(b []byte) (*geojson.Geometry, error) {
+	t := uint64(0)
+	var lengths, icoords []uint64
+	var geometries []*geojson.Geometry
+
+	r := pbfReader{buf: b}
+	for r.more() {
+		field, wireType, err := r.next()
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case field == 1 && wireType == wireVarint:
+			t, err = r.varint()
+		case field == 2:
+			lengths, err = r.varints(wireType, lengths)
+		case field == 3:
+			icoords, err = r.varints(wireType, icoords)
+		case field == 4 && wireType == wireBytes:
+			var m []byte
+			if m, err = r.bytes(); err == nil {
+				var g *geojson.Geometry
+				g, err = d.readGeometry(m)
+				geometries = append(geometries, g)
+			}
+		default:
+			err = r.skip(wireType)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if t >= uint64(len(geometryTypeNames)) {
+		return nil, fmt.Errorf("Unsupported Geobuf geometry type: %d", t)
+	}
+	if len(icoords)%d.dim != 0 {
+		return nil, errors.New("Invalid Geobuf coordinates")
+	}
+	coords := make([]int64, len(icoords))
+	for i, c := range icoords {
+		coords[i] = unzigzag(c)
+	}
+
+	g := &geojson.Geometry{
+		Type: geometryTypeNames[t],
+	}
+	var err error
+	switch g.Type {
+	case "Point":
+		if len(coords) > 0 {
+			g.Coordinates = d.readLine(coords[:d.dim], false)[0]
+		}
+	case "MultiPoint", "LineString":
+		g.Coordinates = d.readLine(coords, false)
+	case "MultiLineString", "Polygon":
+		g.Coordinates, err = d.readLines(coords, lengths, g.Type == "Polygon")
+	case "MultiPolygon":
+		g.Coordinates, err = d.readPolygons(coords, lengths)
+	case "GeometryCollection":
+		g.Geometries = geometries
+	}
+	if err != nil {
+		return nil, err
+	}
+	return g, nil
+}
+
+//readLine decodes delta-encoded coordinates, repeating the first point if closed
+func (d *data) readLine(coords []int64, closed bool) [][]float64 {
+	line := make([][]float64, 0, len(coords)/d.dim+1)
+	sum := make([]int64, d.dim)
+	for i := 0; i < len(coords); i += d.dim {
+		pt := make([]float64, d.dim)
+		for j := range pt {
+			sum[j] += coords[i+j]
+			pt[j] = float64(sum[j]) / d.e
+		}
+		line = append(line, pt)
+	}
+	if closed && len(line) > 0 {
+		line = append(line, line[0])
+	}
+	return line
+}
+
+//take returns the coordinates of n points from coords
+func (d *data) take(coords []int64, n uint64) ([]int64, []int64, error) {
+	if n*uint64(d.dim) > uint64(len(coords)) {
+		return nil, nil, errors.New("Invalid Geobuf geometry lengths")
+	}
+	return coords[:n*uint64(d.dim)], coords[n*uint64(d.dim):], nil
+}
+
+func (d *data) readLines(coords []int64, lengths []uint64, closed bool) ([][][]float64, error) {
+	if lengths == nil {
+		if len(coords) == 0 {
+			return [][][]float64{}, nil
+		}
+		return [][][]float64{d.readLine(coords, closed)}, nil
+	}
+
+	lines := make([][][]float64, len(lengths))
+	for i, n := range lengths {
+		var line []int64
+		var err error
+		if line, coords, err = d.take(coords, n); err != nil {
+			return nil, err
+		}
+		lines[i] = d.readLine(line, closed)
+	}
+	return lines, nil
+}
+
+func (d *data) readPolygons(coords []int64, lengths []uint64) ([][][][]float64, error) {
+	if lengths == nil {
+		if len(coords) == 0 {
+			return [][][][]float64{}, nil
+		}
+		return [][][][]float64{{d.readLine(coords, true)}}, nil
+	}
+
+	next := func() (uint64, error) {
+		if len(lengths) == 0 {
+			return 0, errors.New("Invalid Geobuf geometry lengths")
+		}
+		n := lengths[0]
+		lengths = lengths[1:]
+		return n, nil
+	}
+
+	numPolygons, err := next()
+	if err != nil {
+		return nil, err
+	}
+	polygons := make([][][][]float64, 0, len(lengths))
+	for p := uint64(0); p < numPolygons; p++ {
+		numRings, err := next()
+		if err != nil {
+			return nil, err
+		}
+		polygon := make([][][]float64, 0, len(lengths))
+		for i := uint64(0); i < numRings; i++ {
+			n, err := next()
+			if err != nil {
+				return nil, err
+			}
+			var ring []int64
+			if ring, coords, err = d.take(coords, n); err != nil {
+				return nil, err
+			}
+			polygon = append(polygon, d.readLine(ring, true))
+		}
+		polygons = append(polygons, polygon)
+	}
+	return polygons, nil
+}
@@ -0,0 +1,183 @@
+// Copyright 2015 Simon HEGE. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package geobuf
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+)
+
+//Protocol buffers wire types
+const (
+	wireVarint  = 0
+	wireFixed64 = 1
+	wireBytes   = 2
+	wireFixed32 = 5
+)
+
+var errTruncated = errors.New("Invalid Geobuf data: unexpected end of message")
+
+//pbfWriter appends protocol buffers fields to a byte slice
+type pbfWriter struct {
+	buf []byte
+}
+
+func (w *pbfWriter) varint(v uint64) {
+	w.buf = binary.AppendUvarint(w.buf, v)
+}
+
+func (w *pbfWriter) tag(field int, wireType int) {
+	w.varint(uint64(field)<<3 | uint64(wireType))
+}
+
+func (w *pbfWriter) varintField(field int, v uint64) {
+	w.tag(field, wireVarint)
+	w.varint(v)
+}
+
+func (w *pbfWriter) svarintField(field int, v int64) {
+	w.varintField(field, zigzag(v))
+}
+
+func (w *pbfWriter) doubleField(field int, v float64) {
+	w.tag(field, wireFixed64)
+	w.buf = binary.LittleEndian.AppendUint64(w.buf, math.Float64bits(v))
+}
+
+func (w *pbfWriter) bytesField(field int, b []byte) {
+	w.tag(field, wireBytes)
+	w.varint(uint64(len(b)))
+	w.buf = append(w.buf, b...)
+}
+
+func (w *pbfWriter) stringField(field int, s string) {
+	w.bytesField(field, []byte(s))
+}
+
+func (w *pbfWriter) packedVarintField(field int, values []uint64) {
+	if len(values) == 0 {
+		return
+	}
+	var packed pbfWriter
+	for _, v := range values {
+		packed.varint(v)
+	}
+	w.bytesField(field, packed.buf)
+}
+
+func (w *pbfWriter) packedSVarintField(field int, values []int64) {
+	if len(values) == 0 {
+		return
+	}
+	var packed pbfWriter
+	for _, v := range values {
+		packed.varint(zigzag(v))
+	}
+	w.bytesField(field, packed.buf)
+}
+
+func zigzag(v int64) uint64 {
+	return uint64(v<<1) ^ uint64(v>>63)
+}
+
+func unzigzag(v uint64) int64 {
+	return int64(v>>1) ^ -int64(v&1)
+}
+
+//pbfReader reads protocol buffers fields from a byte slice
+type pbfReader struct {
+	buf []byte
+	pos int
+}
+
+func (r *pbfReader) more() bool {
+	return r.pos < len(r.buf)
+}
+
+func (r *pbfReader) varint() (uint64, error) {
+	v, n := binary.Uvarint(r.buf[r.pos:])
+	if n <= 0 {
+		return 0, errTruncated
+	}
+	r.pos += n
+	return v, nil
+}
+
+//next reads the next field tag
+func (r *pbfReader) next() (field int, wireType int, err error) {
+	v, err := r.varint()
+	if err != nil {
+		return 0, 0, err
+	}
+	return int(v >> 3), int(v & 7), nil
+}
+
+func (r *pbfReader) bytes() ([]byte, error) {
+	n, err := r.varint()
+	if err != nil {
+		return nil, err
+	}
+	if n > uint64(len(r.buf)-r.pos) {
+		return nil, errTruncated
+	}
+	b := r.buf[r.pos : r.pos+int(n)]
+	r.pos += int(n)
+	return b, nil
+}
+
+func (r *pbfReader) double() (float64, error) {
+	if len(r.buf)-r.pos < 8 {
+		return 0, errTruncated
+	}
+	v := math.Float64frombits(binary.LittleEndian.Uint64(r.buf[r.pos:]))
+	r.pos += 8
+	return v, nil
+}
+
+//varints reads a repeated varint field, packed or not
+func (r *pbfReader) varints(wireType int, values []uint64) ([]uint64, error) {
+	if wireType == wireVarint {
+		v, err := r.varint()
+		return append(values, v), err
+	}
+	b, err := r.bytes()
+	if err != nil {
+		return nil, err
+	}
+	packed := pbfReader{buf: b}
+	for packed.more() {
+		v, err := packed.varint()
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, v)
+	}
+	return values, nil
+}
+
+//skip skips the value of a field
+func (r *pbfReader) skip(wireType int) error {
+	var n int
+	switch wireType {
+	case wireVarint:
+		_, err := r.varint()
+		return err
+	case wireBytes:
+		_, err := r.bytes()
+		return err
+	case wireFixed64:
+		n = 8
+	case wireFixed32:
+		n = 4
+	default:
+		return errors.New("Invalid Geobuf data: unsupported wire type")
+	}
+	if len(r.buf)-r.pos < n {
+		return errTruncated
+	}
+	r.pos += n
+	return nil
+}
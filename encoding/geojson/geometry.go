@@ -3,7 +3,7 @@
 // license that can be found in the LICENSE file.
 
 /*
-Package geojson implements decoding of GeoJSON objects as defined at http://geojson.org/.
+Package geojson implements encoding and decoding of GeoJSON objects as defined at http://geojson.org/.
 
 GeoJSON is a format for encoding a variety of geographic data structures.
 */
@@ -62,6 +62,81 @@ type Geometry struct {
 	Geometries  []*Geometry `json:"geometries,omitempty"`
 }
 
+var errInvalidCoordinates = errors.New("Invalid GeoJSON coordinates")
+
+//positionFromInterface converts GeoJSON coordinates, either typed or as decoded by encoding/json, to a position
+func positionFromInterface(icoord interface{}) ([]float64, error) {
+	switch c := icoord.(type) {
+	case []float64:
+		return c, nil
+	case []interface{}:
+		coord := make([]float64, len(c))
+		for i, ic := range c {
+			v, ok := ic.(float64)
+			if !ok {
+				return nil, errInvalidCoordinates
+			}
+			coord[i] = v
+		}
+		return coord, nil
+	}
+	return nil, errInvalidCoordinates
+}
+
+//positionsFromInterface converts GeoJSON coordinates to an array of positions
+func positionsFromInterface(icoord interface{}) ([][]float64, error) {
+	switch c := icoord.(type) {
+	case [][]float64:
+		return c, nil
+	case []interface{}:
+		coord := make([][]float64, len(c))
+		for i, ic := range c {
+			var err error
+			if coord[i], err = positionFromInterface(ic); err != nil {
+				return nil, err
+			}
+		}
+		return coord, nil
+	}
+	return nil, errInvalidCoordinates
+}
+
+//ringsFromInterface converts GeoJSON coordinates to an array of arrays of positions
+func ringsFromInterface(icoord interface{}) ([][][]float64, error) {
+	switch c := icoord.(type) {
+	case [][][]float64:
+		return c, nil
+	case []interface{}:
+		coord := make([][][]float64, len(c))
+		for i, ic := range c {
+			var err error
+			if coord[i], err = positionsFromInterface(ic); err != nil {
+				return nil, err
+			}
+		}
+		return coord, nil
+	}
+	return nil, errInvalidCoordinates
+}
+
+//polygonsFromInterface converts GeoJSON coordinates to an array of polygons coordinates
+func polygonsFromInterface(icoord interface{}) ([][][][]float64, error) {
+	switch c := icoord.(type) {
+	case [][][][]float64:
+		return c, nil
+	case []interface{}:
+		coord := make([][][][]float64, len(c))
+		for i, ic := range c {
+			var err error
+			if coord[i], err = ringsFromInterface(ic); err != nil {
+				return nil, err
+			}
+		}
+		return coord, nil
+	}
+	return nil, errInvalidCoordinates
+}
+
 func pointFromCoordinates(coord []float64) (geom.Geometry, error) {
 	switch len(coord) {
 	case 2:
@@ -128,32 +203,6 @@ func linestringFromCoordinates(coord [][]float64) (geom.Geometry, error) {
 	return nil, errors.New("Unsupported GeoJSON coordinates dimension")
 }
 
-func polygonFromInterface(icoord []interface{}) (geom.Geometry, error) {
-
-	var coord [][][]float64
-
-	for _, icring := range icoord {
-
-		iring := icring.([]interface{})
-		var ring [][]float64
-
-		for _, irpoint := range iring {
-
-			ipoint := irpoint.([]interface{})
-
-			var point []float64
-			for _, ipcoordinates := range ipoint {
-				point = append(point, ipcoordinates.(float64))
-			}
-
-			ring = append(ring, point)
-		}
-
-		coord = append(coord, ring)
-	}
-
-	return polygonFromCoordinates(coord)
-}
 func polygonFromCoordinates(coord [][][]float64) (geom.Geometry, error) {
 
 	if len(coord) == 0 {
@@ -218,40 +267,6 @@ func polygonFromCoordinates(coord [][][]float64) (geom.Geometry, error) {
 
 }
 
-func multiPolygonFromInterface(icoord []interface{}) (geom.Geometry, error) {
-
-	var coord [][][][]float64
-
-	for _, icpoly := range icoord {
-
-		ipoly := icpoly.([]interface{})
-		var poly [][][]float64
-
-		for _, icring := range ipoly {
-
-			iring := icring.([]interface{})
-			var ring [][]float64
-
-			for _, irpoint := range iring {
-
-				ipoint := irpoint.([]interface{})
-
-				var point []float64
-				for _, ipcoordinates := range ipoint {
-					point = append(point, ipcoordinates.(float64))
-				}
-
-				ring = append(ring, point)
-			}
-
-			poly = append(poly, ring)
-		}
-
-		coord = append(coord, poly)
-	}
-
-	return multiPolygonFromCoordinates(coord)
-}
 func multiPolygonFromCoordinates(coord [][][][]float64) (geom.Geometry, error) {
 
 	if len(coord) == 0 {
@@ -318,30 +333,121 @@ func multiPolygonFromCoordinates(coord [][][][]float64) (geom.Geometry, error) {
 
 }
 
+func multiPointFromCoordinates(coord [][]float64) (geom.Geometry, error) {
+	g, err := linestringFromCoordinates(coord)
+	if err != nil {
+		return nil, err
+	}
+
+	switch g := g.(type) {
+	case geom.LineStringZ:
+		return geom.MultiPointZ(g), nil
+	case geom.LineStringZM:
+		return geom.MultiPointZM(g), nil
+	}
+	return geom.MultiPoint(g.(geom.LineString)), nil
+}
+
+func multiLineStringFromCoordinates(coord [][][]float64) (geom.Geometry, error) {
+	g, err := polygonFromCoordinates(coord)
+	if err != nil {
+		return nil, err
+	}
+
+	switch g := g.(type) {
+	case geom.PolygonZ:
+		return geom.MultiLineStringZ(g), nil
+	case geom.PolygonZM:
+		return geom.MultiLineStringZM(g), nil
+	}
+	return geom.MultiLineString(g.(geom.Polygon)), nil
+}
+
+func geometryCollectionFromGeometries(geometries []*Geometry) (geom.Geometry, error) {
+
+	geoms := make([]geom.Geometry, len(geometries))
+	isZ, isZM := len(geometries) > 0, len(geometries) > 0
+	for i, child := range geometries {
+		g, err := FromGeoJSON(*child)
+		if err != nil {
+			return nil, err
+		}
+		_, ok := g.(geom.GeometryZ)
+		isZ = isZ && ok
+		_, ok = g.(geom.GeometryZM)
+		isZM = isZM && ok
+		geoms[i] = g
+	}
+
+	if isZM {
+		ret := make(geom.GeometryCollectionZM, len(geoms))
+		for i, g := range geoms {
+			ret[i] = g.(geom.GeometryZM)
+		}
+		return ret, nil
+	}
+	if isZ {
+		ret := make(geom.GeometryCollectionZ, len(geoms))
+		for i, g := range geoms {
+			ret[i] = g.(geom.GeometryZ)
+		}
+		return ret, nil
+	}
+	return geom.GeometryCollection(geoms), nil
+}
+
 //FromGeoJSON creates a new Geometry object based on the GeoJSON geometry.
 //
 //It returns an error if the geometry type is not supported.
+//Coordinates may be typed (as created by ToGeoJSON) or generic (as decoded by encoding/json).
 //Coordinates array with 3 values are interpreted as X/Y/Z.
 func FromGeoJSON(g Geometry) (geom.Geometry, error) {
 
 	switch g.Type {
 	case "Point":
-		coord := g.Coordinates.([]float64)
+		coord, err := positionFromInterface(g.Coordinates)
+		if err != nil {
+			return nil, err
+		}
 
 		return pointFromCoordinates(coord)
 	case "LineString":
-		coord := g.Coordinates.([][]float64)
+		coord, err := positionsFromInterface(g.Coordinates)
+		if err != nil {
+			return nil, err
+		}
 
 		return linestringFromCoordinates(coord)
 	case "Polygon":
-		coord := g.Coordinates.([]interface{})
+		coord, err := ringsFromInterface(g.Coordinates)
+		if err != nil {
+			return nil, err
+		}
 
-		return polygonFromInterface(coord)
+		return polygonFromCoordinates(coord)
+	case "MultiPoint":
+		coord, err := positionsFromInterface(g.Coordinates)
+		if err != nil {
+			return nil, err
+		}
 
+		return multiPointFromCoordinates(coord)
+	case "MultiLineString":
+		coord, err := ringsFromInterface(g.Coordinates)
+		if err != nil {
+			return nil, err
+		}
+
+		return multiLineStringFromCoordinates(coord)
 	case "MultiPolygon":
-		coord := g.Coordinates.([]interface{})
+		coord, err := polygonsFromInterface(g.Coordinates)
+		if err != nil {
+			return nil, err
+		}
 
-		return multiPolygonFromInterface(coord)
+		return multiPolygonFromCoordinates(coord)
+	case "GeometryCollection":
+		return geometryCollectionFromGeometries(g.Geometries)
 	}
 
 	return nil, errors.New("Unsupported geometry type: " + g.Type)
@@ -0,0 +1,805 @@
+// Copyright 2015 Simon HEGE. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+/*
+Package gml implements encoding and decoding of GML 3.2 geometries.
+
+GML is the XML grammar defined by the OGC to express geographical features. It is described
+in OGC 07-036 OpenGIS® Geography Markup Language (GML) Encoding Standard Version 3.2.1
+http://portal.opengeospatial.org/files/?artifact_id=20509 (also ISO 19136)
+
+The supported elements are Point, LineString, Polygon (with LinearRing exterior and interior
+boundaries), MultiPoint, MultiCurve, MultiSurface and MultiGeometry. Coordinates are read from
+pos, posList, pointProperty and coordinates elements.
+
+The srsDimension attribute selects the geometry family: 2 gives the plain types and 3 gives the
+Z variants. The srsName attribute is exposed as an SRID, 0 if it is not recognized. Axis order is kept
+as written in the document, no swapping is done for geographic reference systems.
+*/
+package gml
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/xeonx/geom"
+)
+
+const (
+	//Namespace is the XML namespace of GML 3.2
+	Namespace = "http://www.opengis.net/gml/3.2"
+	//Namespace31 is the XML namespace of GML 3.1 (and earlier), accepted when decoding
+	Namespace31 = "http://www.opengis.net/gml"
+)
+
+//node is a generic XML element, used as intermediate representation when decoding
+type node struct {
+	XMLName xml.Name
+	Attrs   []xml.Attr `xml:",any,attr"`
+	Nodes   []node     `xml:",any"`
+	Text    string     `xml:",chardata"`
+}
+
+//attr returns the value of the attribute with the given local name
+func (n *node) attr(name string) (string, bool) {
+	for _, a := range n.Attrs {
+		if a.Name.Local == name {
+			return a.Value, true
+		}
+	}
+	return "", false
+}
+
+//context holds the reference system information inherited by nested elements
+type context struct {
+	srid int
+	dim  int
+}
+
+//with returns the context updated with the srsName and srsDimension attributes of n
+func (ctx context) with(n *node) (context, error) {
+	if srsName, ok := n.attr("srsName"); ok {
+		//Unknown reference systems are not an error: the geometry is still decoded, with a SRID of 0
+		ctx.srid, _ = ParseSRSName(srsName)
+	}
+	if srsDimension, ok := n.attr("srsDimension"); ok {
+		dim, err := strconv.Atoi(strings.TrimSpace(srsDimension))
+		if err != nil {
+			return ctx, fmt.Errorf("Invalid GML srsDimension '%s'", srsDimension)
+		}
+		if dim != 2 && dim != 3 {
+			return ctx, fmt.Errorf("Unsupported GML srsDimension. Expecting 2 or 3, got %d", dim)
+		}
+		ctx.dim = dim
+	}
+	return ctx, nil
+}
+
+//ParseSRSName returns the SRID referenced by a srsName attribute, or 0 and an error if it is not recognized.
+//
+//The common notations are supported: EPSG:4326, urn:ogc:def:crs:EPSG::4326,
+//http://www.opengis.net/def/crs/EPSG/0/4326 and http://www.opengis.net/gml/srs/epsg.xml#4326.
+//The OGC CRS84, CRS83 and CRS27 reference systems (urn:ogc:def:crs:OGC:1.3:CRS84,
+//http://www.opengis.net/def/crs/OGC/1.3/CRS84, CRS:84, ...) are mapped to EPSG 4326, 4269 and 4267.
+func ParseSRSName(srsName string) (int, error) {
+	s := strings.TrimSpace(srsName)
+	upper := strings.ToUpper(s)
+	for name, srid := range ogcCRS {
+		if strings.HasSuffix(upper, ":"+name) || strings.HasSuffix(upper, "/"+name) || upper == name || upper == "CRS:"+name[3:] {
+			return srid, nil
+		}
+	}
+
+	i := strings.LastIndexAny(s, ":/#")
+	srid, err := strconv.Atoi(s[i+1:])
+	if err != nil || srid < 0 {
+		return 0, fmt.Errorf("Unsupported GML srsName '%s'", srsName)
+	}
+	return srid, nil
+}
+
+//ogcCRS holds the EPSG codes of the OGC reference systems, which have a longitude/latitude axis order
+var ogcCRS = map[string]int{
+	"CRS84": 4326,
+	"CRS83": 4269,
+	"CRS27": 4267,
+}
+
+//SRSName returns the srsName used when encoding the given SRID
+func SRSName(srid int) string {
+	return "urn:ogc:def:crs:EPSG::" + strconv.Itoa(srid)
+}
+
+//isGeometryElement returns true if the element is a supported GML geometry
+func isGeometryElement(name xml.Name) bool {
+	if name.Space != Namespace && name.Space != Namespace31 {
+		return false
+	}
+	switch name.Local {
+	case "Point", "LineString", "Polygon", "MultiPoint", "MultiCurve", "MultiLineString", "MultiSurface", "MultiPolygon", "MultiGeometry":
+		return true
+	}
+	return false
+}
+
+//A Decoder reads and decodes GML geometries from an input stream
+type Decoder struct {
+	d *xml.Decoder
+}
+
+//NewDecoder returns a new decoder that reads from r.
+func NewDecoder(r io.Reader) *Decoder {
+	return &Decoder{
+		d: xml.NewDecoder(r),
+	}
+}
+
+//Decode decodes the next GML geometry element of the stream, skipping any other content.
+//This allows reading the geometries embedded in a document such as a WFS response.
+//
+//It returns the geometry and the SRID given by its srsName (0 if absent).
+//At the end of the input stream, Decode returns io.EOF.
+func (dec *Decoder) Decode() (geom.Geometry, int, error) {
+	for {
+		tok, err := dec.d.Token()
+		if err != nil {
+			return nil, 0, err
+		}
+		if start, ok := tok.(xml.StartElement); ok && isGeometryElement(start.Name) {
+			return dec.DecodeElement(&start)
+		}
+	}
+}
+
+//DecodeElement decodes the geometry element starting with start.
+//It is useful when the caller already walks the XML tokens itself.
+func (dec *Decoder) DecodeElement(start *xml.StartElement) (geom.Geometry, int, error) {
+	var n node
+	if err := dec.d.DecodeElement(&n, start); err != nil {
+		return nil, 0, err
+	}
+	ctx, err := context{}.with(&n)
+	if err != nil {
+		return nil, 0, err
+	}
+	g, err := decodeGeometry(&n, ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	return g, ctx.srid, nil
+}
+
+//Read reads a single GML geometry
+func Read(r io.Reader) (geom.Geometry, int, error) {
+	g, srid, err := NewDecoder(r).Decode()
+	if err == io.EOF {
+		return nil, 0, errors.New("No GML geometry found")
+	}
+	return g, srid, err
+}
+
+func decodeGeometry(n *node, ctx context) (geom.Geometry, error) {
+	ctx, err := ctx.with(n)
+	if err != nil {
+		return nil, err
+	}
+
+	switch n.XMLName.Local {
+	case "Point":
+		return decodePoint(n, ctx)
+	case "LineString":
+		c, err := decodeCoordinates(n, ctx)
+		if err != nil {
+			return nil, err
+		}
+		return c.lineString(), nil
+	case "Polygon":
+		return decodePolygon(n, ctx)
+	case "MultiPoint":
+		return decodeMultiPoint(n, ctx)
+	case "MultiCurve", "MultiLineString":
+		return decodeMultiLineString(n, ctx)
+	case "MultiSurface", "MultiPolygon":
+		return decodeMultiPolygon(n, ctx)
+	case "MultiGeometry":
+		return decodeMultiGeometry(n, ctx)
+	}
+
+	return nil, fmt.Errorf("Unsupported GML geometry element: %s", n.XMLName.Local)
+}
+
+//coordinates is a decoded sequence of positions
+type coordinates struct {
+	dim    int
+	values []float64
+}
+
+func (c *coordinates) append(other coordinates) error {
+	if len(other.values) == 0 {
+		return nil
+	}
+	if len(c.values) > 0 && c.dim != other.dim {
+		return fmt.Errorf("Inconsistent GML coordinates dimension. Expecting %d, got %d", c.dim, other.dim)
+	}
+	c.dim = other.dim
+	c.values = append(c.values, other.values...)
+	return nil
+}
+
+func (c coordinates) len() int {
+	if c.dim == 0 {
+		return 0
+	}
+	return len(c.values) / c.dim
+}
+
+func (c coordinates) lineString() geom.Geometry {
+	if c.dim == 3 {
+		line := make(geom.LineStringZ, c.len())
+		for i := range line {
+			line[i] = geom.PointZ{Point: geom.Point{X: c.values[3*i], Y: c.values[3*i+1]}, Z: c.values[3*i+2]}
+		}
+		return line
+	}
+	line := make(geom.LineString, c.len())
+	for i := range line {
+		line[i] = geom.Point{X: c.values[2*i], Y: c.values[2*i+1]}
+	}
+	return line
+}
+
+//parseValues parses a whitespace separated list of numbers
+func parseValues(s string) ([]float64, error) {
+	fields := strings.Fields(s)
+	values := make([]float64, len(fields))
+	for i, f := range fields {
+		v, err := strconv.ParseFloat(f, 64)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid GML coordinate '%s'", f)
+		}
+		values[i] = v
+	}
+	return values, nil
+}
+
+//decodePos decodes a pos or posList element
+func decodePos(n *node, ctx context, single bool) (coordinates, error) {
+	ctx, err := ctx.with(n)
+	if err != nil {
+		return coordinates{}, err
+	}
+	values, err := parseValues(n.Text)
+	if err != nil {
+		return coordinates{}, err
+	}
+	dim := ctx.dim
+	if dim == 0 {
+		dim = 2
+		if single {
+			dim = len(values)
+		}
+	}
+	if dim != 2 && dim != 3 {
+		return coordinates{}, fmt.Errorf("Unsupported GML coordinates dimension. Expecting 2 or 3, got %d", dim)
+	}
+	if len(values)%dim != 0 || (single && len(values) != dim) {
+		return coordinates{}, fmt.Errorf("Invalid GML %s: %d values for dimension %d", n.XMLName.Local, len(values), dim)
+	}
+	return coordinates{dim: dim, values: values}, nil
+}
+
+//decodeLegacyCoordinates decodes a GML 2 coordinates element
+func decodeLegacyCoordinates(n *node) (coordinates, error) {
+	decimal, ok := n.attr("decimal")
+	if !ok {
+		decimal = "."
+	}
+	cs, ok := n.attr("cs")
+	if !ok {
+		cs = ","
+	}
+	ts, ok := n.attr("ts")
+	if !ok {
+		ts = " "
+	}
+
+	var c coordinates
+	for _, tuple := range strings.FieldsFunc(n.Text, func(r rune) bool { return strings.ContainsRune(ts, r) || r == '\n' || r == '\t' || r == '\r' }) {
+		parts := strings.Split(tuple, cs)
+		values := make([]float64, len(parts))
+		for i, p := range parts {
+			v, err := strconv.ParseFloat(strings.Replace(strings.TrimSpace(p), decimal, ".", 1), 64)
+			if err != nil {
+				return coordinates{}, fmt.Errorf("Invalid GML coordinate '%s'", p)
+			}
+			values[i] = v
+		}
+		if len(values) != 2 && len(values) != 3 {
+			return coordinates{}, fmt.Errorf("Unsupported GML coordinates dimension. Expecting 2 or 3, got %d", len(values))
+		}
+		if err := c.append(coordinates{dim: len(values), values: values}); err != nil {
+			return coordinates{}, err
+		}
+	}
+	return c, nil
+}
+
+//decodeCoordinates decodes the positions of a Point, LineString or LinearRing element
+func decodeCoordinates(n *node, ctx context) (coordinates, error) {
+	var c coordinates
+	for i := range n.Nodes {
+		child := &n.Nodes[i]
+
+		var other coordinates
+		var err error
+		switch child.XMLName.Local {
+		case "pos":
+			other, err = decodePos(child, ctx, true)
+		case "posList":
+			other, err = decodePos(child, ctx, false)
+		case "coordinates":
+			other, err = decodeLegacyCoordinates(child)
+		case "pointProperty", "pointRep":
+			for j := range child.Nodes {
+				if child.Nodes[j].XMLName.Local == "Point" {
+					var pctx context
+					if pctx, err = ctx.with(&child.Nodes[j]); err == nil {
+						other, err = decodeCoordinates(&child.Nodes[j], pctx)
+					}
+				}
+			}
+		default:
+			continue
+		}
+		if err != nil {
+			return coordinates{}, err
+		}
+		if err := c.append(other); err != nil {
+			return coordinates{}, err
+		}
+	}
+	return c, nil
+}
+
+func decodePoint(n *node, ctx context) (geom.Geometry, error) {
+	c, err := decodeCoordinates(n, ctx)
+	if err != nil {
+		return nil, err
+	}
+	if c.len() != 1 {
+		return nil, fmt.Errorf("Invalid GML Point: expecting 1 position, got %d", c.len())
+	}
+	pt := geom.Point{X: c.values[0], Y: c.values[1]}
+	if c.dim == 3 {
+		return &geom.PointZ{Point: pt, Z: c.values[2]}, nil
+	}
+	return &pt, nil
+}
+
+func decodePolygon(n *node, ctx context) (geom.Geometry, error) {
+
+	var rings []coordinates
+	for i := range n.Nodes {
+		boundary := &n.Nodes[i]
+		switch boundary.XMLName.Local {
+		case "exterior", "interior", "outerBoundaryIs", "innerBoundaryIs":
+		default:
+			continue
+		}
+		for j := range boundary.Nodes {
+			ring := &boundary.Nodes[j]
+			if ring.XMLName.Local != "LinearRing" {
+				return nil, fmt.Errorf("Unsupported GML ring element: %s", ring.XMLName.Local)
+			}
+			rctx, err := ctx.with(ring)
+			if err != nil {
+				return nil, err
+			}
+			c, err := decodeCoordinates(ring, rctx)
+			if err != nil {
+				return nil, err
+			}
+			if len(rings) > 0 && c.dim != rings[0].dim {
+				return nil, errors.New("Inconsistent GML coordinates dimension in Polygon")
+			}
+			rings = append(rings, c)
+		}
+	}
+
+	if len(rings) > 0 && rings[0].dim == 3 {
+		p := make(geom.PolygonZ, len(rings))
+		for i, c := range rings {
+			p[i] = c.lineString().(geom.LineStringZ)
+		}
+		return p, nil
+	}
+	p := make(geom.Polygon, len(rings))
+	for i, c := range rings {
+		p[i] = c.lineString().(geom.LineString)
+	}
+	return p, nil
+}
+
+//members decodes the geometries held by the member elements of a multi-geometry
+func members(n *node, ctx context, names ...string) ([]geom.Geometry, error) {
+	var geoms []geom.Geometry
+	for i := range n.Nodes {
+		child := &n.Nodes[i]
+		if !isMember(child.XMLName.Local, names) {
+			continue
+		}
+		for j := range child.Nodes {
+			g, err := decodeGeometry(&child.Nodes[j], ctx)
+			if err != nil {
+				return nil, err
+			}
+			geoms = append(geoms, g)
+		}
+	}
+	return geoms, nil
+}
+
+func isMember(name string, names []string) bool {
+	for _, n := range names {
+		if name == n {
+			return true
+		}
+	}
+	return false
+}
+
+//hasZ returns true if all geometries are three-dimensional
+func hasZ(geoms []geom.Geometry) bool {
+	for _, g := range geoms {
+		if _, ok := g.(geom.GeometryZ); !ok {
+			return false
+		}
+	}
+	return len(geoms) > 0
+}
+
+func decodeMultiPoint(n *node, ctx context) (geom.Geometry, error) {
+	geoms, err := members(n, ctx, "pointMember", "pointMembers")
+	if err != nil {
+		return nil, err
+	}
+
+	if hasZ(geoms) {
+		points := make(geom.MultiPointZ, len(geoms))
+		for i, g := range geoms {
+			pt, ok := g.(*geom.PointZ)
+			if !ok {
+				return nil, fmt.Errorf("Unexpected child geometry type in MultiPointZ: %s", reflect.TypeOf(g).String())
+			}
+			points[i] = *pt
+		}
+		return points, nil
+	}
+
+	points := make(geom.MultiPoint, len(geoms))
+	for i, g := range geoms {
+		pt, ok := g.(*geom.Point)
+		if !ok {
+			return nil, fmt.Errorf("Unexpected child geometry type in MultiPoint: %s", reflect.TypeOf(g).String())
+		}
+		points[i] = *pt
+	}
+	return points, nil
+}
+
+func decodeMultiLineString(n *node, ctx context) (geom.Geometry, error) {
+	geoms, err := members(n, ctx, "curveMember", "curveMembers", "lineStringMember", "lineStringMembers")
+	if err != nil {
+		return nil, err
+	}
+
+	var ok bool
+	if hasZ(geoms) {
+		lines := make(geom.MultiLineStringZ, len(geoms))
+		for i, g := range geoms {
+			lines[i], ok = g.(geom.LineStringZ)
+			if !ok {
+				return nil, fmt.Errorf("Unexpected child geometry type in MultiLineStringZ: %s", reflect.TypeOf(g).String())
+			}
+		}
+		return lines, nil
+	}
+
+	lines := make(geom.MultiLineString, len(geoms))
+	for i, g := range geoms {
+		lines[i], ok = g.(geom.LineString)
+		if !ok {
+			return nil, fmt.Errorf("Unexpected child geometry type in MultiLineString: %s", reflect.TypeOf(g).String())
+		}
+	}
+	return lines, nil
+}
+
+func decodeMultiPolygon(n *node, ctx context) (geom.Geometry, error) {
+	geoms, err := members(n, ctx, "surfaceMember", "surfaceMembers", "polygonMember", "polygonMembers")
+	if err != nil {
+		return nil, err
+	}
+
+	var ok bool
+	if hasZ(geoms) {
+		polygons := make(geom.MultiPolygonZ, len(geoms))
+		for i, g := range geoms {
+			polygons[i], ok = g.(geom.PolygonZ)
+			if !ok {
+				return nil, fmt.Errorf("Unexpected child geometry type in MultiPolygonZ: %s", reflect.TypeOf(g).String())
+			}
+		}
+		return polygons, nil
+	}
+
+	polygons := make(geom.MultiPolygon, len(geoms))
+	for i, g := range geoms {
+		polygons[i], ok = g.(geom.Polygon)
+		if !ok {
+			return nil, fmt.Errorf("Unexpected child geometry type in MultiPolygon: %s", reflect.TypeOf(g).String())
+		}
+	}
+	return polygons, nil
+}
+
+func decodeMultiGeometry(n *node, ctx context) (geom.Geometry, error) {
+	geoms, err := members(n, ctx, "geometryMember", "geometryMembers")
+	if err != nil {
+		return nil, err
+	}
+
+	if hasZ(geoms) {
+		collection := make(geom.GeometryCollectionZ, len(geoms))
+		for i, g := range geoms {
+			collection[i] = g.(geom.GeometryZ)
+		}
+		return collection, nil
+	}
+
+	return geom.GeometryCollection(geoms), nil
+}
+
+//An Encoder writes GML geometries to an output stream
+type Encoder struct {
+	w io.Writer
+
+	//IDPrefix is used to generate the gml:id attributes required by the GML 3.2 schema:
+	//identifiers are the prefix followed by a counter, unique for all the geometries written by the encoder.
+	//If empty, DefaultIDPrefix is used.
+	IDPrefix string
+
+	count int
+}
+
+//DefaultIDPrefix is the prefix of the gml:id attributes used when the IDPrefix of an Encoder is empty
+const DefaultIDPrefix = "geom"
+
+//NewEncoder returns a new encoder that writes to w.
+func NewEncoder(w io.Writer) *Encoder {
+	return &Encoder{
+		w: w,
+	}
+}
+
+//Encode writes the GML representation of g, with a srsName referencing srid (omitted if 0).
+//
+//It returns an error if the geometry type is not supported. GML has no M component,
+//so only the plain and Z geometries can be encoded.
+func (enc *Encoder) Encode(g geom.Geometry, srid int) error {
+	var b bytes.Buffer
+
+	root := fmt.Sprintf(` xmlns:gml="%s"`, Namespace)
+	if srid != 0 {
+		root += fmt.Sprintf(` srsName="%s"`, SRSName(srid))
+	}
+	if err := enc.encodeGeometry(&b, g, root, 0); err != nil {
+		return err
+	}
+
+	_, err := enc.w.Write(b.Bytes())
+	return err
+}
+
+//Write writes the GML representation of g
+func Write(w io.Writer, g geom.Geometry, srid int) error {
+	return NewEncoder(w).Encode(g, srid)
+}
+
+//open writes the start tag of a geometry element
+func (enc *Encoder) open(b *bytes.Buffer, name string, attrs string, dim int, parentDim int) {
+	fmt.Fprintf(b, "<gml:%s", name)
+	prefix := enc.IDPrefix
+	if prefix == "" {
+		prefix = DefaultIDPrefix
+	}
+	enc.count++
+	b.WriteString(` gml:id="`)
+	xml.EscapeText(b, []byte(prefix+strconv.Itoa(enc.count)))
+	b.WriteString(`"`)
+	b.WriteString(attrs)
+	if dim != parentDim {
+		fmt.Fprintf(b, ` srsDimension="%d"`, dim)
+	}
+	b.WriteString(">")
+}
+
+func writeFloat(b *bytes.Buffer, v float64) {
+	b.WriteString(strconv.FormatFloat(v, 'f', -1, 64))
+}
+
+func writePos(b *bytes.Buffer, pt geom.Point) {
+	b.WriteString("<gml:pos>")
+	writeFloat(b, pt.X)
+	b.WriteString(" ")
+	writeFloat(b, pt.Y)
+	b.WriteString("</gml:pos>")
+}
+
+func writePosZ(b *bytes.Buffer, pt geom.PointZ) {
+	b.WriteString("<gml:pos>")
+	writeFloat(b, pt.X)
+	b.WriteString(" ")
+	writeFloat(b, pt.Y)
+	b.WriteString(" ")
+	writeFloat(b, pt.Z)
+	b.WriteString("</gml:pos>")
+}
+
+func writePosList(b *bytes.Buffer, l geom.LineString) {
+	b.WriteString("<gml:posList>")
+	for i, pt := range l {
+		if i > 0 {
+			b.WriteString(" ")
+		}
+		writeFloat(b, pt.X)
+		b.WriteString(" ")
+		writeFloat(b, pt.Y)
+	}
+	b.WriteString("</gml:posList>")
+}
+
+func writePosListZ(b *bytes.Buffer, l geom.LineStringZ) {
+	b.WriteString("<gml:posList>")
+	for i, pt := range l {
+		if i > 0 {
+			b.WriteString(" ")
+		}
+		writeFloat(b, pt.X)
+		b.WriteString(" ")
+		writeFloat(b, pt.Y)
+		b.WriteString(" ")
+		writeFloat(b, pt.Z)
+	}
+	b.WriteString("</gml:posList>")
+}
+
+func (enc *Encoder) encodeGeometry(b *bytes.Buffer, g geom.Geometry, attrs string, parentDim int) error {
+	switch g := g.(type) {
+	/* Point */
+	case *geom.Point:
+		enc.open(b, "Point", attrs, 2, parentDim)
+		writePos(b, *g)
+		b.WriteString("</gml:Point>")
+	case *geom.PointZ:
+		enc.open(b, "Point", attrs, 3, parentDim)
+		writePosZ(b, *g)
+		b.WriteString("</gml:Point>")
+	/* LineString */
+	case geom.LineString:
+		enc.open(b, "LineString", attrs, 2, parentDim)
+		writePosList(b, g)
+		b.WriteString("</gml:LineString>")
+	case geom.LineStringZ:
+		enc.open(b, "LineString", attrs, 3, parentDim)
+		writePosListZ(b, g)
+		b.WriteString("</gml:LineString>")
+	/* Polygon */
+	case geom.Polygon:
+		enc.open(b, "Polygon", attrs, 2, parentDim)
+		for i, ring := range g {
+			boundary := "interior"
+			if i == 0 {
+				boundary = "exterior"
+			}
+			fmt.Fprintf(b, "<gml:%s><gml:LinearRing>", boundary)
+			writePosList(b, ring)
+			fmt.Fprintf(b, "</gml:LinearRing></gml:%s>", boundary)
+		}
+		b.WriteString("</gml:Polygon>")
+	case geom.PolygonZ:
+		enc.open(b, "Polygon", attrs, 3, parentDim)
+		for i, ring := range g {
+			boundary := "interior"
+			if i == 0 {
+				boundary = "exterior"
+			}
+			fmt.Fprintf(b, "<gml:%s><gml:LinearRing>", boundary)
+			writePosListZ(b, ring)
+			fmt.Fprintf(b, "</gml:LinearRing></gml:%s>", boundary)
+		}
+		b.WriteString("</gml:Polygon>")
+	/* MultiPoint */
+	case geom.MultiPoint:
+		enc.open(b, "MultiPoint", attrs, 2, parentDim)
+		for i := range g {
+			b.WriteString("<gml:pointMember>")
+			enc.encodeGeometry(b, &g[i], "", 2)
+			b.WriteString("</gml:pointMember>")
+		}
+		b.WriteString("</gml:MultiPoint>")
+	case geom.MultiPointZ:
+		enc.open(b, "MultiPoint", attrs, 3, parentDim)
+		for i := range g {
+			b.WriteString("<gml:pointMember>")
+			enc.encodeGeometry(b, &g[i], "", 3)
+			b.WriteString("</gml:pointMember>")
+		}
+		b.WriteString("</gml:MultiPoint>")
+	/* MultiLineString */
+	case geom.MultiLineString:
+		enc.open(b, "MultiCurve", attrs, 2, parentDim)
+		for _, line := range g {
+			b.WriteString("<gml:curveMember>")
+			enc.encodeGeometry(b, line, "", 2)
+			b.WriteString("</gml:curveMember>")
+		}
+		b.WriteString("</gml:MultiCurve>")
+	case geom.MultiLineStringZ:
+		enc.open(b, "MultiCurve", attrs, 3, parentDim)
+		for _, line := range g {
+			b.WriteString("<gml:curveMember>")
+			enc.encodeGeometry(b, line, "", 3)
+			b.WriteString("</gml:curveMember>")
+		}
+		b.WriteString("</gml:MultiCurve>")
+	/* MultiPolygon */
+	case geom.MultiPolygon:
+		enc.open(b, "MultiSurface", attrs, 2, parentDim)
+		for _, polygon := range g {
+			b.WriteString("<gml:surfaceMember>")
+			enc.encodeGeometry(b, polygon, "", 2)
+			b.WriteString("</gml:surfaceMember>")
+		}
+		b.WriteString("</gml:MultiSurface>")
+	case geom.MultiPolygonZ:
+		enc.open(b, "MultiSurface", attrs, 3, parentDim)
+		for _, polygon := range g {
+			b.WriteString("<gml:surfaceMember>")
+			enc.encodeGeometry(b, polygon, "", 3)
+			b.WriteString("</gml:surfaceMember>")
+		}
+		b.WriteString("</gml:MultiSurface>")
+	/* GeometryCollection */
+	case geom.GeometryCollection:
+		enc.open(b, "MultiGeometry", attrs, 2, parentDim)
+		for _, child := range g {
+			b.WriteString("<gml:geometryMember>")
+			if err := enc.encodeGeometry(b, child, "", 2); err != nil {
+				return err
+			}
+			b.WriteString("</gml:geometryMember>")
+		}
+		b.WriteString("</gml:MultiGeometry>")
+	case geom.GeometryCollectionZ:
+		enc.open(b, "MultiGeometry", attrs, 3, parentDim)
+		for _, child := range g {
+			b.WriteString("<gml:geometryMember>")
+			if err := enc.encodeGeometry(b, child, "", 3); err != nil {
+				return err
+			}
+			b.WriteString("</gml:geometryMember>")
+		}
+		b.WriteString("</gml:MultiGeometry>")
+	default:
+		return fmt.Errorf("Unsupported geometry type: %v", reflect.TypeOf(g))
+	}
+	return nil
+}
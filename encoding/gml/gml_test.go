@@ -0,0 +1,108 @@
+// Copyright 2015 Simon HEGE. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package gml
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/xeonx/geom"
+)
+
+func TestParseSRSName(t *testing.T) {
+	tests := []struct {
+		srsName string
+		want    int
+	}{
+		{"EPSG:4326", 4326},
+		{"urn:ogc:def:crs:EPSG::2154", 2154},
+		{"urn:ogc:def:crs:EPSG:6.6:4326", 4326},
+		{"http://www.opengis.net/def/crs/EPSG/0/3857", 3857},
+		{"http://www.opengis.net/gml/srs/epsg.xml#4326", 4326},
+		{"urn:ogc:def:crs:OGC:1.3:CRS84", 4326},
+		{"urn:ogc:def:crs:OGC::CRS84", 4326},
+		{"http://www.opengis.net/def/crs/OGC/1.3/CRS84", 4326},
+		{"CRS:84", 4326},
+		{"OGC:CRS84", 4326},
+		{"urn:ogc:def:crs:OGC:1.3:CRS83", 4269},
+		{"urn:ogc:def:crs:OGC:1.3:CRS27", 4267},
+	}
+	for _, tt := range tests {
+		if got, err := ParseSRSName(tt.srsName); err != nil || got != tt.want {
+			t.Errorf("ParseSRSName(%q) = %d, %v, want %d", tt.srsName, got, err, tt.want)
+		}
+	}
+	for _, s := range []string{"", "foo", "urn:ogc:def:crs:EPSG::-1", "http://example.com/crs/local"} {
+		if got, err := ParseSRSName(s); err == nil || got != 0 {
+			t.Errorf("ParseSRSName(%q) = %d, %v, want an error", s, got, err)
+		}
+	}
+}
+
+func TestReadUnknownSRSName(t *testing.T) {
+	tests := []struct {
+		gml  string
+		srid int
+	}{
+		{`<gml:Point xmlns:gml="http://www.opengis.net/gml/3.2" srsName="urn:ogc:def:crs:OGC:1.3:CRS84"><gml:pos>2.35 48.85</gml:pos></gml:Point>`, 4326},
+		{`<gml:Point xmlns:gml="http://www.opengis.net/gml/3.2" srsName="http://example.com/crs/local"><gml:pos>2.35 48.85</gml:pos></gml:Point>`, 0},
+	}
+	for _, tt := range tests {
+		g, srid, err := Read(strings.NewReader(tt.gml))
+		if err != nil {
+			t.Errorf("Read(%q): %v", tt.gml, err)
+			continue
+		}
+		if want := (&geom.Point{X: 2.35, Y: 48.85}); srid != tt.srid || !reflect.DeepEqual(g, want) {
+			t.Errorf("Read(%q) = %#v, %d, want %#v, %d", tt.gml, g, srid, want, tt.srid)
+		}
+	}
+}
+
+func TestWriteUnsupported(t *testing.T) {
+	for _, g := range []geom.Geometry{nil, geom.GeometryCollection{nil}, geom.LineStringM{}} {
+		var b strings.Builder
+		if err := Write(&b, g, 0); err == nil {
+			t.Errorf("Write(%#v) should fail", g)
+		}
+	}
+}
+
+func TestWriteIDs(t *testing.T) {
+	var b strings.Builder
+	if err := Write(&b, &geom.Point{X: 1, Y: 2}, 4326); err != nil {
+		t.Fatal(err)
+	}
+	want := `<gml:Point gml:id="geom1" xmlns:gml="http://www.opengis.net/gml/3.2" srsName="urn:ogc:def:crs:EPSG::4326" srsDimension="2"><gml:pos>1 2</gml:pos></gml:Point>`
+	if b.String() != want {
+		t.Errorf("Write = %s, want %s", b.String(), want)
+	}
+
+	b.Reset()
+	enc := NewEncoder(&b)
+	enc.IDPrefix = "f."
+	g := geom.GeometryCollection{&geom.Point{X: 1, Y: 2}, geom.MultiPoint{{X: 3, Y: 4}}}
+	if err := enc.Encode(g, 0); err != nil {
+		t.Fatal(err)
+	}
+	if err := enc.Encode(&geom.Point{X: 5, Y: 6}, 0); err != nil {
+		t.Fatal(err)
+	}
+	for i, id := range []string{"f.1", "f.2", "f.3", "f.4", "f.5"} {
+		if strings.Count(b.String(), `gml:id="`+id+`"`) != 1 {
+			t.Errorf("identifier %d: %s not found once in %s", i, id, b.String())
+		}
+	}
+
+	//Identifiers are ignored when decoding
+	b.Reset()
+	if err := Write(&b, g, 0); err != nil {
+		t.Fatal(err)
+	}
+	if r, _, err := Read(strings.NewReader(b.String())); err != nil || !reflect.DeepEqual(r, g) {
+		t.Errorf("Read(%s) = %#v, %v", b.String(), r, err)
+	}
+}
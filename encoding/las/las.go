@@ -0,0 +1,335 @@
+// Copyright 2015 Simon HEGE. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+/*
+Package las implements reading of LAS point clouds as defined by the ASPRS LAS specification.
+
+Versions 1.0 to 1.4 are supported, with point data record formats 0 to 10.
+Compressed LAZ files are not supported.
+
+Point records are streamed as PointZ, or as PointZM with the intensity or the GPS time as M.
+Coordinates are returned with the scale and offset of the header applied.
+*/
+package las
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+
+	"github.com/xeonx/geom"
+)
+
+//Signature is the file signature of LAS files
+const Signature = "LASF"
+
+var errInvalidChunkSize = errors.New("Invalid chunk size")
+
+//Size of the public header block of LAS 1.0 to 1.2
+const headerSize12 = 227
+
+//minRecordLength is the minimal record length of each point data record format
+var minRecordLength = []uint16{20, 28, 26, 34, 57, 63, 30, 36, 38, 59, 67}
+
+//Header represents the public header block of a LAS file
+type Header struct {
+	FileSourceID       uint16
+	GlobalEncoding     uint16
+	VersionMajor       uint8
+	VersionMinor       uint8
+	SystemIdentifier   string
+	GeneratingSoftware string
+	CreationDayOfYear  uint16
+	CreationYear       uint16
+
+	HeaderSize            uint16
+	PointDataOffset       uint32
+	VLRCount              uint32
+	PointDataFormat       uint8
+	PointDataRecordLength uint16
+	PointCount            uint64
+
+	Scale  geom.PointZ
+	Offset geom.PointZ
+	Bounds geom.EnvelopeZ
+}
+
+//HasGPSTime returns true if the point records include a GPS time
+func (h *Header) HasGPSTime() bool {
+	return gpsTimeOffset(h.PointDataFormat) > 0
+}
+
+//gpsTimeOffset returns the offset of the GPS time in a point record, or 0 if the format has none
+func gpsTimeOffset(format uint8) int {
+	switch format {
+	case 1, 3, 4, 5:
+		return 20
+	case 6, 7, 8, 9, 10:
+		return 22
+	}
+	return 0
+}
+
+//Measure selects the point record attribute used as M value
+type Measure int
+
+const (
+	//Intensity uses the pulse return magnitude
+	Intensity Measure = iota
+	//GPSTime uses the time at which the point was acquired
+	GPSTime
+)
+
+//A Reader reads point records from a LAS file
+type Reader struct {
+	r      *bufio.Reader
+	record []byte
+	read   uint64
+
+	Header Header
+
+	//Filter, if not nil, restricts the returned points to the ones inside the envelope
+	Filter *geom.Envelope
+}
+
+//NewReader reads the header of a LAS file and returns a reader positioned on the first point record.
+//Variable length records are skipped.
+func NewReader(r io.Reader) (*Reader, error) {
+	br := bufio.NewReader(r)
+
+	b := make([]byte, headerSize12)
+	if _, err := io.ReadFull(br, b); err != nil {
+		return nil, err
+	}
+	if string(b[0:4]) != Signature {
+		return nil, errors.New("Invalid LAS signature")
+	}
+
+	le := binary.LittleEndian
+	f64 := func(offset int) float64 {
+		return math.Float64frombits(le.Uint64(b[offset:]))
+	}
+	h := Header{
+		FileSourceID:          le.Uint16(b[4:]),
+		GlobalEncoding:        le.Uint16(b[6:]),
+		VersionMajor:          b[24],
+		VersionMinor:          b[25],
+		SystemIdentifier:      strings.TrimRight(string(b[26:58]), "\x00 "),
+		GeneratingSoftware:    strings.TrimRight(string(b[58:90]), "\x00 "),
+		CreationDayOfYear:     le.Uint16(b[90:]),
+		CreationYear:          le.Uint16(b[92:]),
+		HeaderSize:            le.Uint16(b[94:]),
+		PointDataOffset:       le.Uint32(b[96:]),
+		VLRCount:              le.Uint32(b[100:]),
+		PointDataFormat:       b[104],
+		PointDataRecordLength: le.Uint16(b[105:]),
+		PointCount:            uint64(le.Uint32(b[107:])),
+		Scale:                 geom.PointZ{Point: geom.Point{X: f64(131), Y: f64(139)}, Z: f64(147)},
+		Offset:                geom.PointZ{Point: geom.Point{X: f64(155), Y: f64(163)}, Z: f64(171)},
+	}
+	h.Bounds.Max = geom.PointZ{Point: geom.Point{X: f64(179), Y: f64(195)}, Z: f64(211)}
+	h.Bounds.Min = geom.PointZ{Point: geom.Point{X: f64(187), Y: f64(203)}, Z: f64(219)}
+
+	if h.VersionMajor != 1 || h.VersionMinor > 4 {
+		return nil, fmt.Errorf("Unsupported LAS version: %d.%d", h.VersionMajor, h.VersionMinor)
+	}
+	if h.PointDataFormat&0xC0 != 0 {
+		return nil, errors.New("Compressed LAZ data is not supported")
+	}
+	if int(h.PointDataFormat) >= len(minRecordLength) {
+		return nil, fmt.Errorf("Unsupported LAS point data record format: %d", h.PointDataFormat)
+	}
+	if h.PointDataRecordLength < minRecordLength[h.PointDataFormat] {
+		return nil, fmt.Errorf("Invalid LAS point data record length %d for format %d", h.PointDataRecordLength, h.PointDataFormat)
+	}
+	if h.HeaderSize < headerSize12 || h.PointDataOffset < uint32(h.HeaderSize) {
+		return nil, errors.New("Invalid LAS header size or point data offset")
+	}
+
+	//Remaining of the header (LAS 1.3 and 1.4 fields)
+	extra := make([]byte, int(h.HeaderSize)-headerSize12)
+	if _, err := io.ReadFull(br, extra); err != nil {
+		return nil, err
+	}
+	//LAS 1.4 64 bits point count, at offset 247
+	if h.VersionMinor >= 4 && len(extra) >= 247+8-headerSize12 {
+		if count := le.Uint64(extra[247-headerSize12:]); count > 0 {
+			h.PointCount = count
+		}
+	}
+
+	//Variable length records
+	if _, err := br.Discard(int(h.PointDataOffset - uint32(h.HeaderSize))); err != nil {
+		return nil, err
+	}
+
+	return &Reader{
+		r:      br,
+		record: make([]byte, h.PointDataRecordLength),
+		Header: h,
+	}, nil
+}
+
+//next reads the next point record matching the filter.
+//It returns io.EOF when all the points have been read.
+func (r *Reader) next() (geom.PointZ, error) {
+	h := &r.Header
+	le := binary.LittleEndian
+	for {
+		if r.read >= h.PointCount {
+			return geom.PointZ{}, io.EOF
+		}
+		if _, err := io.ReadFull(r.r, r.record); err != nil {
+			if err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			return geom.PointZ{}, err
+		}
+		r.read++
+
+		pt := geom.PointZ{
+			Point: geom.Point{
+				X: float64(int32(le.Uint32(r.record[0:])))*h.Scale.X + h.Offset.X,
+				Y: float64(int32(le.Uint32(r.record[4:])))*h.Scale.Y + h.Offset.Y,
+			},
+		}
+		if r.Filter != nil && (pt.X < r.Filter.Min.X || pt.X > r.Filter.Max.X || pt.Y < r.Filter.Min.Y || pt.Y > r.Filter.Max.Y) {
+			continue
+		}
+		pt.Z = float64(int32(le.Uint32(r.record[8:])))*h.Scale.Z + h.Offset.Z
+		return pt, nil
+	}
+}
+
+//measure returns the M value of the current record
+func (r *Reader) measure(m Measure) (float64, error) {
+	switch m {
+	case Intensity:
+		return float64(binary.LittleEndian.Uint16(r.record[12:])), nil
+	case GPSTime:
+		offset := gpsTimeOffset(r.Header.PointDataFormat)
+		return math.Float64frombits(binary.LittleEndian.Uint64(r.record[offset:])), nil
+	}
+	return 0, fmt.Errorf("Unsupported LAS measure: %d", m)
+}
+
+//ReadPointZ reads the next point. It returns io.EOF when all the points have been read.
+func (r *Reader) ReadPointZ() (*geom.PointZ, error) {
+	pt, err := r.next()
+	if err != nil {
+		return nil, err
+	}
+	return &pt, nil
+}
+
+//ReadPointZM reads the next point, with the given attribute as M value.
+//It returns io.EOF when all the points have been read.
+func (r *Reader) ReadPointZM(m Measure) (*geom.PointZM, error) {
+	if m == GPSTime && !r.Header.HasGPSTime() {
+		return nil, fmt.Errorf("LAS point data record format %d has no GPS time", r.Header.PointDataFormat)
+	}
+	pt, err := r.next()
+	if err != nil {
+		return nil, err
+	}
+	v, err := r.measure(m)
+	if err != nil {
+		return nil, err
+	}
+	return &geom.PointZM{PointZ: pt, M: v}, nil
+}
+
+//ReadChunkZ reads up to n points.
+//It returns io.EOF, and no points, when all the points have been read.
+func (r *Reader) ReadChunkZ(n int) (geom.MultiPointZ, error) {
+	if n <= 0 {
+		return nil, errInvalidChunkSize
+	}
+	points := make(geom.MultiPointZ, 0, n)
+	for len(points) < n {
+		pt, err := r.next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return points, err
+		}
+		points = append(points, pt)
+	}
+	if len(points) == 0 {
+		return nil, io.EOF
+	}
+	return points, nil
+}
+
+//ReadChunkZM reads up to n points, with the given attribute as M value.
+//It returns io.EOF, and no points, when all the points have been read.
+func (r *Reader) ReadChunkZM(n int, m Measure) (geom.MultiPointZM, error) {
+	if n <= 0 {
+		return nil, errInvalidChunkSize
+	}
+	points := make(geom.MultiPointZM, 0, n)
+	for len(points) < n {
+		pt, err := r.ReadPointZM(m)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return points, err
+		}
+		points = append(points, *pt)
+	}
+	if len(points) == 0 {
+		return nil, io.EOF
+	}
+	return points, nil
+}
+
+//IterateZ reads all the remaining points, by chunks of at most n points, and calls f on each chunk.
+//It stops at the first error returned by f.
+func (r *Reader) IterateZ(n int, f func(geom.MultiPointZ) error) error {
+	for {
+		points, err := r.ReadChunkZ(n)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := f(points); err != nil {
+			return err
+		}
+	}
+}
+
+//IterateZM reads all the remaining points, by chunks of at most n points, and calls f on each chunk.
+//It stops at the first error returned by f.
+func (r *Reader) IterateZM(n int, m Measure, f func(geom.MultiPointZM) error) error {
+	for {
+		points, err := r.ReadChunkZM(n, m)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := f(points); err != nil {
+			return err
+		}
+	}
+}
+
+//ReadAllZ reads all the remaining points
+func (r *Reader) ReadAllZ() (geom.MultiPointZ, error) {
+	var points geom.MultiPointZ
+	err := r.IterateZ(4096, func(chunk geom.MultiPointZ) error {
+		points = append(points, chunk...)
+		return nil
+	})
+	return points, err
+}
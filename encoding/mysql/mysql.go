@@ -0,0 +1,72 @@
+// Copyright 2015 Simon HEGE. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+/*
+Package mysql implements encoding and decoding of the MySQL internal geometry format.
+
+MySQL stores geometries as a 4-byte little endian SRID followed by the WKB representation
+of the geometry. It is described at https://dev.mysql.com/doc/refman/8.0/en/gis-data-formats.html
+
+MySQL only supports two-dimensional geometries.
+*/
+package mysql
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+
+	"github.com/xeonx/geom"
+	"github.com/xeonx/geom/encoding/wkb"
+)
+
+//Read reads a MySQL internal geometry. It returns the geometry and its SRID.
+func Read(r io.Reader) (geom.Geometry, int, error) {
+
+	var srid uint32
+	if err := binary.Read(r, binary.LittleEndian, &srid); err != nil {
+		return nil, 0, err
+	}
+
+	g, err := wkb.Read(r)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return g, int(srid), nil
+}
+
+//Write writes the MySQL internal representation of g.
+//It returns an error if the geometry is not two-dimensional.
+func Write(w io.Writer, g geom.Geometry, srid int) error {
+
+	if err := checkDimension(g); err != nil {
+		return err
+	}
+
+	if err := binary.Write(w, binary.LittleEndian, uint32(srid)); err != nil {
+		return err
+	}
+
+	return wkb.Write(w, binary.LittleEndian, g)
+}
+
+//checkDimension returns an error if g, or one of its children, has a Z or M component
+func checkDimension(g geom.Geometry) error {
+	Type, err := wkb.TypeOf(g)
+	if err != nil {
+		return err
+	}
+	if Type.HasZ() || Type.HasM() {
+		return errors.New("Unsupported geometry dimension: MySQL only supports two-dimensional geometries")
+	}
+	if c, ok := g.(geom.GeometryCollection); ok {
+		for _, child := range c {
+			if err := checkDimension(child); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
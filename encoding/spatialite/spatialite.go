@@ -0,0 +1,291 @@
+// Copyright 2015 Simon HEGE. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+/*
+Package spatialite implements encoding and decoding of the SpatiaLite internal BLOB geometry format.
+
+The format wraps a WKB-like geometry with a header holding the SRID and the MBR of the geometry.
+Items of collections are prefixed by an entity mark instead of a byte order. It is described at
+https://www.gaia-gis.it/gaia-sins/BLOB-Geometry.html
+
+Compressed geometries are not supported.
+*/
+package spatialite
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+
+	"github.com/xeonx/geom"
+	"github.com/xeonx/geom/encoding/wkb"
+)
+
+//Marks delimiting the parts of a SpatiaLite BLOB geometry
+const (
+	Start  = 0x00
+	MBREnd = 0x7C
+	Entity = 0x69
+	End    = 0xFE
+)
+
+//Read reads a SpatiaLite BLOB geometry. It returns the geometry and its SRID.
+func Read(r io.Reader) (geom.Geometry, int, error) {
+
+	var header struct {
+		Start     uint8
+		ByteOrder uint8
+	}
+	if err := binary.Read(r, binary.BigEndian, &header); err != nil {
+		return nil, 0, err
+	}
+	if header.Start != Start {
+		return nil, 0, fmt.Errorf("Invalid SpatiaLite start mark. Expecting %d, got '%d'", Start, header.Start)
+	}
+	var byteOrder binary.ByteOrder = binary.BigEndian
+	if header.ByteOrder == wkb.LDR {
+		byteOrder = binary.LittleEndian
+	} else if header.ByteOrder != wkb.XDR {
+		return nil, 0, fmt.Errorf("Invalid SpatiaLite byte order. Expecting %d or %d, got '%d'", wkb.XDR, wkb.LDR, header.ByteOrder)
+	}
+
+	var info struct {
+		SRID   int32
+		MBR    [4]float64
+		MBREnd uint8
+		Type   wkb.Type
+	}
+	if err := binary.Read(r, byteOrder, &info); err != nil {
+		return nil, 0, err
+	}
+	if info.MBREnd != MBREnd {
+		return nil, 0, fmt.Errorf("Invalid SpatiaLite MBR end mark. Expecting %d, got '%d'", MBREnd, info.MBREnd)
+	}
+
+	g, err := readGeometry(r, byteOrder, info.Type)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var end uint8
+	if err := binary.Read(r, byteOrder, &end); err != nil {
+		return nil, 0, err
+	}
+	if end != End {
+		return nil, 0, fmt.Errorf("Invalid SpatiaLite end mark. Expecting %d, got '%d'", End, end)
+	}
+
+	return g, int(info.SRID), nil
+}
+
+//readEntity reads a collection item
+func readEntity(r io.Reader, byteOrder binary.ByteOrder) (geom.Geometry, error) {
+	var entity struct {
+		Mark uint8
+		Type wkb.Type
+	}
+	if err := binary.Read(r, byteOrder, &entity); err != nil {
+		return nil, err
+	}
+	if entity.Mark != Entity {
+		return nil, fmt.Errorf("Invalid SpatiaLite entity mark. Expecting %d, got '%d'", Entity, entity.Mark)
+	}
+	return readGeometry(r, byteOrder, entity.Type)
+}
+
+func readGeometry(r io.Reader, byteOrder binary.ByteOrder, Type wkb.Type) (geom.Geometry, error) {
+
+	if Type >= 1000000 {
+		return nil, fmt.Errorf("Unsupported compressed SpatiaLite geometry type. Got '%d'", Type)
+	}
+
+	switch Type.Flatten() {
+	case wkb.WKBPoint, wkb.WKBLineString, wkb.WKBPolygon:
+		return wkb.ReadGeometry(r, byteOrder, Type)
+	case wkb.WKBMultiPoint, wkb.WKBMultiLineString, wkb.WKBMultiPolygon, wkb.WKBGeometryCollection:
+	default:
+		return nil, fmt.Errorf("Unsupported SpatiaLite geometry type. Got '%d'", Type)
+	}
+
+	var numGeoms uint32
+	if err := binary.Read(r, byteOrder, &numGeoms); err != nil {
+		return nil, err
+	}
+
+	//Collection items are re-encoded as WKB, so that wkb builds the typed collection
+	var b bytes.Buffer
+	if err := toWKB(&b, r, byteOrder, Type, numGeoms); err != nil {
+		return nil, err
+	}
+	return wkb.Read(&b)
+}
+
+//toWKB writes a WKB collection made of the numGeoms SpatiaLite entities read from r
+func toWKB(w io.Writer, r io.Reader, byteOrder binary.ByteOrder, Type wkb.Type, numGeoms uint32) error {
+	if err := writeHeader(w, byteOrder, Type); err != nil {
+		return err
+	}
+	if err := binary.Write(w, byteOrder, numGeoms); err != nil {
+		return err
+	}
+	for i := uint32(0); i < numGeoms; i++ {
+		g, err := readEntity(r, byteOrder)
+		if err != nil {
+			return err
+		}
+		if err := wkb.Write(w, byteOrder, g); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeHeader(w io.Writer, byteOrder binary.ByteOrder, Type wkb.Type) error {
+	var wkbByteOrder uint8 = wkb.XDR
+	if byteOrder == binary.LittleEndian {
+		wkbByteOrder = wkb.LDR
+	}
+	if err := binary.Write(w, byteOrder, wkbByteOrder); err != nil {
+		return err
+	}
+	return binary.Write(w, byteOrder, Type)
+}
+
+//Write writes the SpatiaLite BLOB representation of g, in little endian byte order
+func Write(w io.Writer, g geom.Geometry, srid int) error {
+
+	Type, err := wkb.TypeOf(g)
+	if err != nil {
+		return err
+	}
+
+	byteOrder := binary.LittleEndian
+	e := g.Envelope()
+	header := struct {
+		Start     uint8
+		ByteOrder uint8
+		SRID      int32
+		MBR       [4]float64
+		MBREnd    uint8
+		Type      wkb.Type
+	}{
+		Start:     Start,
+		ByteOrder: wkb.LDR,
+		SRID:      int32(srid),
+		MBR:       [4]float64{e.Min.X, e.Min.Y, e.Max.X, e.Max.Y},
+		MBREnd:    MBREnd,
+		Type:      Type,
+	}
+	if err := binary.Write(w, byteOrder, &header); err != nil {
+		return err
+	}
+
+	if err := writeGeometry(w, byteOrder, g); err != nil {
+		return err
+	}
+
+	return binary.Write(w, byteOrder, uint8(End))
+}
+
+//writeEntity writes a collection item
+func writeEntity(w io.Writer, byteOrder binary.ByteOrder, g geom.Geometry) error {
+	Type, err := wkb.TypeOf(g)
+	if err != nil {
+		return err
+	}
+	switch Type.Flatten() {
+	case wkb.WKBPoint, wkb.WKBLineString, wkb.WKBPolygon:
+	default:
+		return fmt.Errorf("Unsupported SpatiaLite collection item type. Got '%d'", Type)
+	}
+	if err := binary.Write(w, byteOrder, uint8(Entity)); err != nil {
+		return err
+	}
+	if err := binary.Write(w, byteOrder, Type); err != nil {
+		return err
+	}
+	return wkb.WriteGeometry(w, byteOrder, g)
+}
+
+func writeGeometry(w io.Writer, byteOrder binary.ByteOrder, g geom.Geometry) error {
+
+	var items []geom.Geometry
+	switch g := g.(type) {
+	case geom.MultiPoint:
+		for i := range g {
+			items = append(items, &g[i])
+		}
+	case geom.MultiPointZ:
+		for i := range g {
+			items = append(items, &g[i])
+		}
+	case geom.MultiPointM:
+		for i := range g {
+			items = append(items, &g[i])
+		}
+	case geom.MultiPointZM:
+		for i := range g {
+			items = append(items, &g[i])
+		}
+	case geom.MultiLineString:
+		for _, line := range g {
+			items = append(items, line)
+		}
+	case geom.MultiLineStringZ:
+		for _, line := range g {
+			items = append(items, line)
+		}
+	case geom.MultiLineStringM:
+		for _, line := range g {
+			items = append(items, line)
+		}
+	case geom.MultiLineStringZM:
+		for _, line := range g {
+			items = append(items, line)
+		}
+	case geom.MultiPolygon:
+		for _, polygon := range g {
+			items = append(items, polygon)
+		}
+	case geom.MultiPolygonZ:
+		for _, polygon := range g {
+			items = append(items, polygon)
+		}
+	case geom.MultiPolygonM:
+		for _, polygon := range g {
+			items = append(items, polygon)
+		}
+	case geom.MultiPolygonZM:
+		for _, polygon := range g {
+			items = append(items, polygon)
+		}
+	case geom.GeometryCollection:
+		items = g
+	case geom.GeometryCollectionZ:
+		for _, child := range g {
+			items = append(items, child)
+		}
+	case geom.GeometryCollectionM:
+		for _, child := range g {
+			items = append(items, child)
+		}
+	case geom.GeometryCollectionZM:
+		for _, child := range g {
+			items = append(items, child)
+		}
+	default:
+		return wkb.WriteGeometry(w, byteOrder, g)
+	}
+
+	if err := binary.Write(w, byteOrder, uint32(len(items))); err != nil {
+		return err
+	}
+	for _, item := range items {
+		if err := writeEntity(w, byteOrder, item); err != nil {
+			return err
+		}
+	}
+	return nil
+}
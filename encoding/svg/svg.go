@@ -0,0 +1,382 @@
+// Copyright 2015 Simon HEGE. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+/*
+Package svg implements rendering of geometries as SVG.
+
+Lines and polygons are rendered as paths, polygons using the evenodd fill rule so that holes
+are left empty. Points are rendered as circles. Z and M values are ignored.
+
+Geometry coordinates are mapped to the SVG user space by a Viewport, derived from an Envelope.
+As SVG y axis points downward, the y axis is usually flipped for geographic data.
+*/
+package svg
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"strconv"
+
+	"github.com/xeonx/geom"
+)
+
+//Viewport maps geometry coordinates to the SVG user space.
+//The envelope is scaled uniformly to fit the viewport size, minus the margin, and centered.
+type Viewport struct {
+	Width, Height float64
+
+	scale            float64
+	offsetX          float64
+	offsetY          float64
+	flipY            bool
+	originX, originY float64
+}
+
+//NewViewport returns a viewport of the given size (in SVG units) showing the envelope e.
+//If flipY is true, the y axis is reversed so that north is up.
+func NewViewport(e *geom.Envelope, width, height, margin float64, flipY bool) *Viewport {
+	v := &Viewport{
+		Width:  width,
+		Height: height,
+		scale:  1,
+		flipY:  flipY,
+	}
+
+	w, h := e.Max.X-e.Min.X, e.Max.Y-e.Min.Y
+	if math.IsInf(w, 0) || math.IsNaN(w) || math.IsInf(h, 0) || math.IsNaN(h) {
+		return v
+	}
+
+	availableW, availableH := width-2*margin, height-2*margin
+	switch {
+	case w > 0 && h > 0:
+		v.scale = math.Min(availableW/w, availableH/h)
+	case w > 0:
+		v.scale = availableW / w
+	case h > 0:
+		v.scale = availableH / h
+	}
+
+	v.originX, v.originY = e.Min.X, e.Min.Y
+	if flipY {
+		v.originY = e.Max.Y
+	}
+	v.offsetX = (width - w*v.scale) / 2
+	v.offsetY = (height - h*v.scale) / 2
+	return v
+}
+
+//Transform returns the position of pt in the SVG user space
+func (v *Viewport) Transform(pt geom.Point) geom.Point {
+	if v == nil {
+		return pt
+	}
+	y := (pt.Y - v.originY) * v.scale
+	if v.flipY {
+		y = -y
+	}
+	return geom.Point{
+		X: v.offsetX + (pt.X-v.originX)*v.scale,
+		Y: v.offsetY + y,
+	}
+}
+
+//Style holds the presentation attributes of a rendered geometry. Empty values are omitted.
+type Style struct {
+	Fill        string
+	FillOpacity float64
+	Stroke      string
+	StrokeWidth float64
+	Class       string
+}
+
+//DefaultStyle returns the style used when the encoder has no style function
+func DefaultStyle(g geom.Geometry) Style {
+	return Style{
+		Fill:        "#cccccc",
+		Stroke:      "#333333",
+		StrokeWidth: 1,
+	}
+}
+
+//An Encoder writes geometries as SVG documents
+type Encoder struct {
+	w io.Writer
+
+	//Width and Height are the size of the document
+	Width, Height float64
+	//Margin is the space kept around the geometries when the viewport is computed
+	Margin float64
+	//Viewport, if not nil, is used instead of the one computed from the envelope of the geometries
+	Viewport *Viewport
+	//FlipY reverses the y axis of the computed viewport
+	FlipY bool
+	//PointRadius is the radius of the circles representing points
+	PointRadius float64
+	//Precision is the number of decimals written for coordinates. If negative, the shortest exact representation is used.
+	Precision int
+	//Style returns the style of each rendered geometry. Geometry collections are styled per child.
+	Style func(g geom.Geometry) Style
+}
+
+//NewEncoder returns a new encoder writing documents of the given size to w.
+//The y axis is flipped, as expected for geographic coordinates.
+func NewEncoder(w io.Writer, width, height float64) *Encoder {
+	return &Encoder{
+		w:           w,
+		Width:       width,
+		Height:      height,
+		FlipY:       true,
+		PointRadius: 3,
+		Precision:   2,
+		Style:       DefaultStyle,
+	}
+}
+
+//Encode writes a complete SVG document showing the geometries
+func (enc *Encoder) Encode(geoms ...geom.Geometry) error {
+	for _, g := range geoms {
+		if err := checkGeometry(g); err != nil {
+			return err
+		}
+	}
+
+	v := enc.Viewport
+	if v == nil {
+		e := geom.NewEnvelope()
+		for _, g := range geoms {
+			e.Extend(g.Envelope())
+		}
+		v = NewViewport(e, enc.Width, enc.Height, enc.Margin, enc.FlipY)
+	}
+
+	var b bytes.Buffer
+	b.WriteString(`<svg xmlns="http://www.w3.org/2000/svg" width="`)
+	b.WriteString(enc.format(enc.Width))
+	b.WriteString(`" height="`)
+	b.WriteString(enc.format(enc.Height))
+	b.WriteString(`" viewBox="0 0 `)
+	b.WriteString(enc.format(enc.Width))
+	b.WriteString(" ")
+	b.WriteString(enc.format(enc.Height))
+	b.WriteString("\">\n")
+	for _, g := range geoms {
+		if err := enc.encodeGeometry(&b, g, v); err != nil {
+			return err
+		}
+	}
+	b.WriteString("</svg>\n")
+
+	_, err := enc.w.Write(b.Bytes())
+	return err
+}
+
+func (enc *Encoder) format(v float64) string {
+	s := strconv.FormatFloat(v, 'f', enc.Precision, 64)
+	if enc.Precision > 0 {
+		s = trimZeros(s)
+	}
+	return s
+}
+
+//trimZeros removes the trailing zeros of a decimal number
+func trimZeros(s string) string {
+	i := len(s)
+	for i > 0 && s[i-1] == '0' {
+		i--
+	}
+	if i > 0 && s[i-1] == '.' {
+		i--
+	}
+	if i == 0 || s[:i] == "-" {
+		return "0"
+	}
+	return s[:i]
+}
+
+//kind is the rendering of a geometry
+type kind int
+
+const (
+	kindPoint kind = iota
+	kindLine
+	kindArea
+	kindCollection
+)
+
+func kindOf(g geom.Geometry) (kind, error) {
+	switch g.(type) {
+	case *geom.Point, *geom.PointZ, *geom.PointM, *geom.PointZM,
+		geom.MultiPoint, geom.MultiPointZ, geom.MultiPointM, geom.MultiPointZM:
+		return kindPoint, nil
+	case geom.LineString, geom.LineStringZ, geom.LineStringM, geom.LineStringZM,
+		geom.MultiLineString, geom.MultiLineStringZ, geom.MultiLineStringM, geom.MultiLineStringZM:
+		return kindLine, nil
+	case geom.Polygon, geom.PolygonZ, geom.PolygonM, geom.PolygonZM,
+		geom.MultiPolygon, geom.MultiPolygonZ, geom.MultiPolygonM, geom.MultiPolygonZM:
+		return kindArea, nil
+	case geom.GeometryCollection, geom.GeometryCollectionZ, geom.GeometryCollectionM, geom.GeometryCollectionZM:
+		return kindCollection, nil
+	}
+	return 0, fmt.Errorf("Unsupported geometry type: %v", reflect.TypeOf(g))
+}
+
+//checkGeometry returns an error if g, or a geometry of a collection, is not supported
+func checkGeometry(g geom.Geometry) error {
+	k, err := kindOf(g)
+	if err != nil {
+		return err
+	}
+	if k == kindCollection {
+		for _, child := range children(g) {
+			if err := checkGeometry(child); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+//children returns the geometries of a collection
+func children(g geom.Geometry) []geom.Geometry {
+	var c []geom.Geometry
+	switch g := g.(type) {
+	case geom.GeometryCollection:
+		c = g
+	case geom.GeometryCollectionZ:
+		for _, child := range g {
+			c = append(c, child)
+		}
+	case geom.GeometryCollectionM:
+		for _, child := range g {
+			c = append(c, child)
+		}
+	case geom.GeometryCollectionZM:
+		for _, child := range g {
+			c = append(c, child)
+		}
+	}
+	return c
+}
+
+//PathData returns the SVG path data of g, with coordinates mapped by v (which may be nil).
+//Polygon rings are closed and should be filled with the evenodd rule.
+//Points only contribute a moveto command.
+func PathData(g geom.Geometry, v *Viewport) (string, error) {
+	enc := Encoder{Precision: -1}
+	var b bytes.Buffer
+	if err := enc.writePathData(&b, g, v); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+func (enc *Encoder) writePathData(b *bytes.Buffer, g geom.Geometry, v *Viewport) error {
+	k, err := kindOf(g)
+	if err != nil {
+		return err
+	}
+	if k == kindCollection {
+		for _, child := range children(g) {
+			if err := enc.writePathData(b, child, v); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	//Iterate is called once per point set, line or ring
+	return g.Iterate(func(points []geom.Point) error {
+		for i, pt := range points {
+			if k == kindPoint || i == 0 {
+				b.WriteString("M")
+			} else {
+				b.WriteString("L")
+			}
+			pt = v.Transform(pt)
+			b.WriteString(enc.format(pt.X))
+			b.WriteString(" ")
+			b.WriteString(enc.format(pt.Y))
+		}
+		if k == kindArea && len(points) > 0 {
+			b.WriteString("Z")
+		}
+		return nil
+	})
+}
+
+func (enc *Encoder) writeStyle(b *bytes.Buffer, g geom.Geometry, k kind) {
+	style := DefaultStyle
+	if enc.Style != nil {
+		style = enc.Style
+	}
+	s := style(g)
+
+	attr := func(name, value string) {
+		if value != "" {
+			b.WriteString(" " + name + `="`)
+			xml.EscapeText(b, []byte(value))
+			b.WriteString(`"`)
+		}
+	}
+	if k == kindLine {
+		attr("fill", "none")
+	} else {
+		attr("fill", s.Fill)
+	}
+	if s.FillOpacity > 0 && k != kindLine {
+		attr("fill-opacity", enc.format(s.FillOpacity))
+	}
+	attr("stroke", s.Stroke)
+	if s.StrokeWidth > 0 {
+		attr("stroke-width", enc.format(s.StrokeWidth))
+	}
+	if k == kindArea {
+		attr("fill-rule", "evenodd")
+	}
+	attr("class", s.Class)
+}
+
+func (enc *Encoder) encodeGeometry(b *bytes.Buffer, g geom.Geometry, v *Viewport) error {
+	k, err := kindOf(g)
+	if err != nil {
+		return err
+	}
+
+	switch k {
+	case kindCollection:
+		b.WriteString("<g>\n")
+		for _, child := range children(g) {
+			if err := enc.encodeGeometry(b, child, v); err != nil {
+				return err
+			}
+		}
+		b.WriteString("</g>\n")
+	case kindPoint:
+		b.WriteString("<g")
+		enc.writeStyle(b, g, k)
+		b.WriteString(">\n")
+		g.Iterate(func(points []geom.Point) error {
+			for _, pt := range points {
+				pt = v.Transform(pt)
+				fmt.Fprintf(b, "<circle cx=\"%s\" cy=\"%s\" r=\"%s\"/>\n", enc.format(pt.X), enc.format(pt.Y), enc.format(enc.PointRadius))
+			}
+			return nil
+		})
+		b.WriteString("</g>\n")
+	default:
+		b.WriteString(`<path d="`)
+		if err := enc.writePathData(b, g, v); err != nil {
+			return err
+		}
+		b.WriteString(`"`)
+		enc.writeStyle(b, g, k)
+		b.WriteString("/>\n")
+	}
+	return nil
+}
@@ -0,0 +1,24 @@
+// Copyright 2015 Simon HEGE. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package svg
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/xeonx/geom"
+)
+
+func TestPathDataUnsupported(t *testing.T) {
+	for _, g := range []geom.Geometry{nil, geom.GeometryCollection{nil}} {
+		if _, err := PathData(g, nil); err == nil {
+			t.Errorf("PathData(%#v) should fail", g)
+		}
+		var b bytes.Buffer
+		if err := NewEncoder(&b, 100, 100).Encode(g); err == nil {
+			t.Errorf("Encode(%#v) should fail", g)
+		}
+	}
+}
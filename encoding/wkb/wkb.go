@@ -3,12 +3,14 @@
 // license that can be found in the LICENSE file.
 
 /*
-Package wkb implements decoding of WKB objects as defined in OGC 06-103r4.
+Package wkb implements encoding and decoding of WKB objects as defined in OGC 06-103r4.
 
 WKB is a binary format for geometry encoding. It is described in OGC 06-103r4 OpenGIS®
 Implementation Standard for Geographic information - Simple feature access - Part 1:
 Common architecture Version: 1.2.1 2011-05-28
 http://portal.opengeospatial.org/files/?artifact_id=25355 (also ISO/TC211 19125 Part 1)
+
+Z, M and ZM geometries are encoded using the ISO type codes (1000, 2000 and 3000 offsets).
 */
 package wkb
 
@@ -84,6 +86,13 @@ func Read(r io.Reader) (geom.Geometry, error) {
 		return nil, err
 	}
 
+	return ReadGeometry(r, byteOrder, Type)
+}
+
+//ReadGeometry reads the data of a WKB geometry whose byte order and type have already been read.
+//It allows reading formats which wrap WKB data in their own header.
+func ReadGeometry(r io.Reader, byteOrder binary.ByteOrder, Type Type) (geom.Geometry, error) {
+
 	switch Type.Flatten() {
 	case WKBPoint:
 		return readWkbPoint(r, byteOrder, Type)
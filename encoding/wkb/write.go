@@ -0,0 +1,348 @@
+// Copyright 2015 Simon HEGE. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package wkb
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"reflect"
+
+	"github.com/xeonx/geom"
+)
+
+//TypeOf returns the WKB type of the given geometry, including the Z and M offsets.
+//It returns an error if the geometry type is not supported.
+func TypeOf(g geom.Geometry) (Type, error) {
+	switch g.(type) {
+	case *geom.Point:
+		return WKBPoint, nil
+	case *geom.PointZ:
+		return WKBPoint + 1000, nil
+	case *geom.PointM:
+		return WKBPoint + 2000, nil
+	case *geom.PointZM:
+		return WKBPoint + 3000, nil
+	case geom.LineString:
+		return WKBLineString, nil
+	case geom.LineStringZ:
+		return WKBLineString + 1000, nil
+	case geom.LineStringM:
+		return WKBLineString + 2000, nil
+	case geom.LineStringZM:
+		return WKBLineString + 3000, nil
+	case geom.Polygon:
+		return WKBPolygon, nil
+	case geom.PolygonZ:
+		return WKBPolygon + 1000, nil
+	case geom.PolygonM:
+		return WKBPolygon + 2000, nil
+	case geom.PolygonZM:
+		return WKBPolygon + 3000, nil
+	case geom.MultiPoint:
+		return WKBMultiPoint, nil
+	case geom.MultiPointZ:
+		return WKBMultiPoint + 1000, nil
+	case geom.MultiPointM:
+		return WKBMultiPoint + 2000, nil
+	case geom.MultiPointZM:
+		return WKBMultiPoint + 3000, nil
+	case geom.MultiLineString:
+		return WKBMultiLineString, nil
+	case geom.MultiLineStringZ:
+		return WKBMultiLineString + 1000, nil
+	case geom.MultiLineStringM:
+		return WKBMultiLineString + 2000, nil
+	case geom.MultiLineStringZM:
+		return WKBMultiLineString + 3000, nil
+	case geom.MultiPolygon:
+		return WKBMultiPolygon, nil
+	case geom.MultiPolygonZ:
+		return WKBMultiPolygon + 1000, nil
+	case geom.MultiPolygonM:
+		return WKBMultiPolygon + 2000, nil
+	case geom.MultiPolygonZM:
+		return WKBMultiPolygon + 3000, nil
+	case geom.GeometryCollection:
+		return WKBGeometryCollection, nil
+	case geom.GeometryCollectionZ:
+		return WKBGeometryCollection + 1000, nil
+	case geom.GeometryCollectionM:
+		return WKBGeometryCollection + 2000, nil
+	case geom.GeometryCollectionZM:
+		return WKBGeometryCollection + 3000, nil
+	}
+
+	return 0, fmt.Errorf("Unsupported geometry type: %v", reflect.TypeOf(g))
+}
+
+//Write writes the WKB representation of g, using the given byte order
+func Write(w io.Writer, byteOrder binary.ByteOrder, g geom.Geometry) error {
+
+	Type, err := TypeOf(g)
+	if err != nil {
+		return err
+	}
+
+	//Write byte order
+	var wkbByteOrder uint8 = XDR
+	if byteOrder == binary.LittleEndian {
+		wkbByteOrder = LDR
+	}
+	if err := binary.Write(w, binary.BigEndian, wkbByteOrder); err != nil {
+		return err
+	}
+
+	//Write geometry type
+	if err := binary.Write(w, byteOrder, Type); err != nil {
+		return err
+	}
+
+	return WriteGeometry(w, byteOrder, g)
+}
+
+//WriteGeometry writes the data of a WKB geometry, without the byte order and type header.
+//It allows writing formats which wrap WKB data in their own header.
+func WriteGeometry(w io.Writer, byteOrder binary.ByteOrder, g geom.Geometry) error {
+	switch g := g.(type) {
+	/* Point */
+	case *geom.Point:
+		return binary.Write(w, byteOrder, g)
+	case *geom.PointZ:
+		return binary.Write(w, byteOrder, g)
+	case *geom.PointM:
+		return binary.Write(w, byteOrder, g)
+	case *geom.PointZM:
+		return binary.Write(w, byteOrder, g)
+	/* LineString */
+	case geom.LineString:
+		if err := binary.Write(w, byteOrder, uint32(len(g))); err != nil {
+			return err
+		}
+		return binary.Write(w, byteOrder, g)
+	case geom.LineStringZ:
+		if err := binary.Write(w, byteOrder, uint32(len(g))); err != nil {
+			return err
+		}
+		return binary.Write(w, byteOrder, g)
+	case geom.LineStringM:
+		if err := binary.Write(w, byteOrder, uint32(len(g))); err != nil {
+			return err
+		}
+		return binary.Write(w, byteOrder, g)
+	case geom.LineStringZM:
+		if err := binary.Write(w, byteOrder, uint32(len(g))); err != nil {
+			return err
+		}
+		return binary.Write(w, byteOrder, g)
+	/* Polygon */
+	case geom.Polygon:
+		if err := binary.Write(w, byteOrder, uint32(len(g))); err != nil {
+			return err
+		}
+		for _, ring := range g {
+			if err := WriteGeometry(w, byteOrder, ring); err != nil {
+				return err
+			}
+		}
+		return nil
+	case geom.PolygonZ:
+		if err := binary.Write(w, byteOrder, uint32(len(g))); err != nil {
+			return err
+		}
+		for _, ring := range g {
+			if err := WriteGeometry(w, byteOrder, ring); err != nil {
+				return err
+			}
+		}
+		return nil
+	case geom.PolygonM:
+		if err := binary.Write(w, byteOrder, uint32(len(g))); err != nil {
+			return err
+		}
+		for _, ring := range g {
+			if err := WriteGeometry(w, byteOrder, ring); err != nil {
+				return err
+			}
+		}
+		return nil
+	case geom.PolygonZM:
+		if err := binary.Write(w, byteOrder, uint32(len(g))); err != nil {
+			return err
+		}
+		for _, ring := range g {
+			if err := WriteGeometry(w, byteOrder, ring); err != nil {
+				return err
+			}
+		}
+		return nil
+	/* MultiPoint */
+	case geom.MultiPoint:
+		if err := binary.Write(w, byteOrder, uint32(len(g))); err != nil {
+			return err
+		}
+		for i := range g {
+			if err := Write(w, byteOrder, &g[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	case geom.MultiPointZ:
+		if err := binary.Write(w, byteOrder, uint32(len(g))); err != nil {
+			return err
+		}
+		for i := range g {
+			if err := Write(w, byteOrder, &g[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	case geom.MultiPointM:
+		if err := binary.Write(w, byteOrder, uint32(len(g))); err != nil {
+			return err
+		}
+		for i := range g {
+			if err := Write(w, byteOrder, &g[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	case geom.MultiPointZM:
+		if err := binary.Write(w, byteOrder, uint32(len(g))); err != nil {
+			return err
+		}
+		for i := range g {
+			if err := Write(w, byteOrder, &g[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	/* MultiLineString */
+	case geom.MultiLineString:
+		if err := binary.Write(w, byteOrder, uint32(len(g))); err != nil {
+			return err
+		}
+		for _, line := range g {
+			if err := Write(w, byteOrder, line); err != nil {
+				return err
+			}
+		}
+		return nil
+	case geom.MultiLineStringZ:
+		if err := binary.Write(w, byteOrder, uint32(len(g))); err != nil {
+			return err
+		}
+		for _, line := range g {
+			if err := Write(w, byteOrder, line); err != nil {
+				return err
+			}
+		}
+		return nil
+	case geom.MultiLineStringM:
+		if err := binary.Write(w, byteOrder, uint32(len(g))); err != nil {
+			return err
+		}
+		for _, line := range g {
+			if err := Write(w, byteOrder, line); err != nil {
+				return err
+			}
+		}
+		return nil
+	case geom.MultiLineStringZM:
+		if err := binary.Write(w, byteOrder, uint32(len(g))); err != nil {
+			return err
+		}
+		for _, line := range g {
+			if err := Write(w, byteOrder, line); err != nil {
+				return err
+			}
+		}
+		return nil
+	/* MultiPolygon */
+	case geom.MultiPolygon:
+		if err := binary.Write(w, byteOrder, uint32(len(g))); err != nil {
+			return err
+		}
+		for _, polygon := range g {
+			if err := Write(w, byteOrder, polygon); err != nil {
+				return err
+			}
+		}
+		return nil
+	case geom.MultiPolygonZ:
+		if err := binary.Write(w, byteOrder, uint32(len(g))); err != nil {
+			return err
+		}
+		for _, polygon := range g {
+			if err := Write(w, byteOrder, polygon); err != nil {
+				return err
+			}
+		}
+		return nil
+	case geom.MultiPolygonM:
+		if err := binary.Write(w, byteOrder, uint32(len(g))); err != nil {
+			return err
+		}
+		for _, polygon := range g {
+			if err := Write(w, byteOrder, polygon); err != nil {
+				return err
+			}
+		}
+		return nil
+	case geom.MultiPolygonZM:
+		if err := binary.Write(w, byteOrder, uint32(len(g))); err != nil {
+			return err
+		}
+		for _, polygon := range g {
+			if err := Write(w, byteOrder, polygon); err != nil {
+				return err
+			}
+		}
+		return nil
+	/* GeometryCollection */
+	case geom.GeometryCollection:
+		if err := binary.Write(w, byteOrder, uint32(len(g))); err != nil {
+			return err
+		}
+		for _, child := range g {
+			if err := Write(w, byteOrder, child); err != nil {
+				return err
+			}
+		}
+		return nil
+	case geom.GeometryCollectionZ:
+		if err := binary.Write(w, byteOrder, uint32(len(g))); err != nil {
+			return err
+		}
+		for _, child := range g {
+			if err := Write(w, byteOrder, child); err != nil {
+				return err
+			}
+		}
+		return nil
+	case geom.GeometryCollectionM:
+		if err := binary.Write(w, byteOrder, uint32(len(g))); err != nil {
+			return err
+		}
+		for _, child := range g {
+			if err := Write(w, byteOrder, child); err != nil {
+				return err
+			}
+		}
+		return nil
+	case geom.GeometryCollectionZM:
+		if err := binary.Write(w, byteOrder, uint32(len(g))); err != nil {
+			return err
+		}
+		for _, child := range g {
+			if err := Write(w, byteOrder, child); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	return fmt.Errorf("Unsupported geometry type: %v", reflect.TypeOf(g))
+}
@@ -0,0 +1,28 @@
+// Copyright 2015 Simon HEGE. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package wkb
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"github.com/xeonx/geom"
+)
+
+func TestWriteUnsupported(t *testing.T) {
+	for _, g := range []geom.Geometry{nil, geom.GeometryCollection{nil}, geom.GeometryCollection{geom.LineString{}, nil}} {
+		var b bytes.Buffer
+		if err := Write(&b, binary.LittleEndian, g); err == nil {
+			t.Errorf("Write(%#v) should fail", g)
+		}
+		if err := WriteGeometry(&b, binary.LittleEndian, g); err == nil {
+			t.Errorf("WriteGeometry(%#v) should fail", g)
+		}
+	}
+	if _, err := TypeOf(nil); err == nil {
+		t.Error("TypeOf(nil) should fail")
+	}
+}
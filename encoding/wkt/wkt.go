@@ -0,0 +1,750 @@
+// Copyright 2015 Simon HEGE. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+/*
+Package wkt implements encoding and decoding of WKT objects as defined in OGC 06-103r4.
+
+WKT is a text format for geometry encoding. It is described in OGC 06-103r4 OpenGIS®
+Implementation Standard for Geographic information - Simple feature access - Part 1:
+Common architecture Version: 1.2.1 2011-05-28
+http://portal.opengeospatial.org/files/?artifact_id=25355 (also ISO/TC211 19125 Part 1)
+
+The Z, M and ZM tags are supported, either as separate words (POINT Z) or as suffixes (POINTZ).
+When no tag is given, coordinates with 3 values are interpreted as X/Y/Z and coordinates with
+4 values as X/Y/Z/M. Empty points are represented by NaN coordinates, as in WKB.
+*/
+package wkt
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/xeonx/geom"
+)
+
+//dim represents the coordinate dimension of a geometry
+type dim int
+
+const (
+	dimUnknown dim = iota
+	dimXY
+	dimXYZ
+	dimXYM
+	dimXYZM
+)
+
+//size returns the number of values per coordinate
+func (d dim) size() int {
+	switch d {
+	case dimXY:
+		return 2
+	case dimXYZ, dimXYM:
+		return 3
+	case dimXYZM:
+		return 4
+	}
+	return 0
+}
+
+//Read reads a WKT geometry
+func Read(r io.Reader) (geom.Geometry, error) {
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(string(b))
+}
+
+//Parse parses a WKT geometry
+func Parse(s string) (geom.Geometry, error) {
+	p := parser{s: s}
+	g, err := p.geometry(dimUnknown)
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.next(); tok != "" {
+		return nil, fmt.Errorf("Unexpected WKT token '%s' after geometry", tok)
+	}
+	return g, nil
+}
+
+//parser is a recursive descent WKT parser
+type parser struct {
+	s   string
+	pos int
+}
+
+//next returns the next token: a word, a number or a punctuation character
+func (p *parser) next() string {
+	for p.pos < len(p.s) && unicode.IsSpace(rune(p.s[p.pos])) {
+		p.pos++
+	}
+	if p.pos >= len(p.s) {
+		return ""
+	}
+	start := p.pos
+	switch c := p.s[p.pos]; {
+	case c == '(' || c == ')' || c == ',':
+		p.pos++
+	case unicode.IsLetter(rune(c)):
+		for p.pos < len(p.s) && unicode.IsLetter(rune(p.s[p.pos])) {
+			p.pos++
+		}
+	default:
+		for p.pos < len(p.s) && strings.IndexByte("0123456789+-.eE", p.s[p.pos]) >= 0 {
+			p.pos++
+		}
+		if p.pos == start {
+			p.pos++
+		}
+	}
+	return p.s[start:p.pos]
+}
+
+func (p *parser) peek() string {
+	pos := p.pos
+	tok := p.next()
+	p.pos = pos
+	return tok
+}
+
+func (p *parser) expect(tok string) error {
+	if got := p.next(); got != tok {
+		if got == "" {
+			return fmt.Errorf("Unexpected end of WKT, expecting '%s'", tok)
+		}
+		return fmt.Errorf("Unexpected WKT token '%s', expecting '%s'", got, tok)
+	}
+	return nil
+}
+
+//empty consumes an EMPTY token if present
+func (p *parser) empty() bool {
+	if strings.EqualFold(p.peek(), "EMPTY") {
+		p.next()
+		return true
+	}
+	return false
+}
+
+//dimension reads the optional dimension tag of a geometry
+func (p *parser) dimension(name string, parent dim) (string, dim) {
+	upper := strings.ToUpper(name)
+	d := parent
+	for _, tag := range []struct {
+		suffix string
+		dim    dim
+	}{{"ZM", dimXYZM}, {"Z", dimXYZ}, {"M", dimXYM}} {
+		if strings.HasSuffix(upper, tag.suffix) && knownType(strings.TrimSuffix(upper, tag.suffix)) {
+			return strings.TrimSuffix(upper, tag.suffix), tag.dim
+		}
+	}
+	switch strings.ToUpper(p.peek()) {
+	case "Z":
+		d = dimXYZ
+	case "M":
+		d = dimXYM
+	case "ZM":
+		d = dimXYZM
+	default:
+		return upper, d
+	}
+	p.next()
+	return upper, d
+}
+
+func knownType(name string) bool {
+	switch name {
+	case "POINT", "LINESTRING", "POLYGON", "MULTIPOINT", "MULTILINESTRING", "MULTIPOLYGON", "GEOMETRYCOLLECTION":
+		return true
+	}
+	return false
+}
+
+//position reads the values of a single coordinate
+func (p *parser) position(d *dim) ([]float64, error) {
+	var values []float64
+	for {
+		tok := p.peek()
+		if tok == "," || tok == ")" || tok == "" {
+			break
+		}
+		p.next()
+		v, err := strconv.ParseFloat(tok, 64)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid WKT coordinate '%s'", tok)
+		}
+		values = append(values, v)
+	}
+
+	if *d == dimUnknown {
+		switch len(values) {
+		case 2:
+			*d = dimXY
+		case 3:
+			*d = dimXYZ
+		case 4:
+			*d = dimXYZM
+		}
+	}
+	if len(values) != d.size() || len(values) == 0 {
+		return nil, fmt.Errorf("Invalid WKT coordinate: got %d values", len(values))
+	}
+	return values, nil
+}
+
+//positions reads a parenthesized list of coordinates.
+//Coordinates may themselves be parenthesized, as allowed for MultiPoint.
+func (p *parser) positions(d *dim) ([][]float64, error) {
+	if p.empty() {
+		return nil, nil
+	}
+	if err := p.expect("("); err != nil {
+		return nil, err
+	}
+	var positions [][]float64
+	for {
+		parenthesized := p.peek() == "("
+		if parenthesized {
+			p.next()
+		}
+		pos, err := p.position(d)
+		if err != nil {
+			return nil, err
+		}
+		if parenthesized {
+			if err := p.expect(")"); err != nil {
+				return nil, err
+			}
+		}
+		positions = append(positions, pos)
+		if p.peek() != "," {
+			break
+		}
+		p.next()
+	}
+	return positions, p.expect(")")
+}
+
+//lists reads a parenthesized list of coordinates lists
+func (p *parser) lists(d *dim) ([][][]float64, error) {
+	if p.empty() {
+		return nil, nil
+	}
+	if err := p.expect("("); err != nil {
+		return nil, err
+	}
+	var lists [][][]float64
+	for {
+		positions, err := p.positions(d)
+		if err != nil {
+			return nil, err
+		}
+		lists = append(lists, positions)
+		if p.peek() != "," {
+			break
+		}
+		p.next()
+	}
+	return lists, p.expect(")")
+}
+
+func (p *parser) geometry(parent dim) (geom.Geometry, error) {
+	word := p.next()
+	if word == "" {
+		return nil, errors.New("Unexpected end of WKT, expecting a geometry")
+	}
+	name, d := p.dimension(word, parent)
+
+	switch name {
+	case "POINT":
+		if p.empty() {
+			if d == dimUnknown {
+				d = dimXY
+			}
+			nan := math.NaN()
+			return point([]float64{nan, nan, nan, nan}[:d.size()], d), nil
+		}
+		if err := p.expect("("); err != nil {
+			return nil, err
+		}
+		pos, err := p.position(&d)
+		if err != nil {
+			return nil, err
+		}
+		return point(pos, d), p.expect(")")
+	case "LINESTRING":
+		positions, err := p.positions(&d)
+		if err != nil {
+			return nil, err
+		}
+		return lineString(positions, d), nil
+	case "POLYGON":
+		lists, err := p.lists(&d)
+		if err != nil {
+			return nil, err
+		}
+		return polygon(lists, d), nil
+	case "MULTIPOINT":
+		positions, err := p.positions(&d)
+		if err != nil {
+			return nil, err
+		}
+		return multiPoint(positions, d), nil
+	case "MULTILINESTRING":
+		lists, err := p.lists(&d)
+		if err != nil {
+			return nil, err
+		}
+		return multiLineString(lists, d), nil
+	case "MULTIPOLYGON":
+		var polygons [][][][]float64
+		if !p.empty() {
+			if err := p.expect("("); err != nil {
+				return nil, err
+			}
+			for {
+				lists, err := p.lists(&d)
+				if err != nil {
+					return nil, err
+				}
+				polygons = append(polygons, lists)
+				if p.peek() != "," {
+					break
+				}
+				p.next()
+			}
+			if err := p.expect(")"); err != nil {
+				return nil, err
+			}
+		}
+		return multiPolygon(polygons, d), nil
+	case "GEOMETRYCOLLECTION":
+		var geoms []geom.Geometry
+		if !p.empty() {
+			if err := p.expect("("); err != nil {
+				return nil, err
+			}
+			for {
+				g, err := p.geometry(d)
+				if err != nil {
+					return nil, err
+				}
+				geoms = append(geoms, g)
+				if p.peek() != "," {
+					break
+				}
+				p.next()
+			}
+			if err := p.expect(")"); err != nil {
+				return nil, err
+			}
+		}
+		return geometryCollection(geoms, d)
+	}
+
+	return nil, fmt.Errorf("Unsupported WKT geometry type: %s", word)
+}
+
+func point(c []float64, d dim) geom.Geometry {
+	pt := geom.Point{X: c[0], Y: c[1]}
+	switch d {
+	case dimXYZ:
+		return &geom.PointZ{Point: pt, Z: c[2]}
+	case dimXYM:
+		return &geom.PointM{Point: pt, M: c[2]}
+	case dimXYZM:
+		return &geom.PointZM{PointZ: geom.PointZ{Point: pt, Z: c[2]}, M: c[3]}
+	}
+	return &pt
+}
+
+func lineString(c [][]float64, d dim) geom.Geometry {
+	switch d {
+	case dimXYZ:
+		line := make(geom.LineStringZ, len(c))
+		for i := range c {
+			line[i] = *point(c[i], d).(*geom.PointZ)
+		}
+		return line
+	case dimXYM:
+		line := make(geom.LineStringM, len(c))
+		for i := range c {
+			line[i] = *point(c[i], d).(*geom.PointM)
+		}
+		return line
+	case dimXYZM:
+		line := make(geom.LineStringZM, len(c))
+		for i := range c {
+			line[i] = *point(c[i], d).(*geom.PointZM)
+		}
+		return line
+	}
+	line := make(geom.LineString, len(c))
+	for i := range c {
+		line[i] = *point(c[i], d).(*geom.Point)
+	}
+	return line
+}
+
+func polygon(c [][][]float64, d dim) geom.Geometry {
+	switch d {
+	case dimXYZ:
+		p := make(geom.PolygonZ, len(c))
+		for i := range c {
+			p[i] = lineString(c[i], d).(geom.LineStringZ)
+		}
+		return p
+	case dimXYM:
+		p := make(geom.PolygonM, len(c))
+		for i := range c {
+			p[i] = lineString(c[i], d).(geom.LineStringM)
+		}
+		return p
+	case dimXYZM:
+		p := make(geom.PolygonZM, len(c))
+		for i := range c {
+			p[i] = lineString(c[i], d).(geom.LineStringZM)
+		}
+		return p
+	}
+	p := make(geom.Polygon, len(c))
+	for i := range c {
+		p[i] = lineString(c[i], d).(geom.LineString)
+	}
+	return p
+}
+
+func multiPoint(c [][]float64, d dim) geom.Geometry {
+	switch line := lineString(c, d).(type) {
+	case geom.LineStringZ:
+		return geom.MultiPointZ(line)
+	case geom.LineStringM:
+		return geom.MultiPointM(line)
+	case geom.LineStringZM:
+		return geom.MultiPointZM(line)
+	case geom.LineString:
+		return geom.MultiPoint(line)
+	}
+	return nil
+}
+
+func multiLineString(c [][][]float64, d dim) geom.Geometry {
+	switch p := polygon(c, d).(type) {
+	case geom.PolygonZ:
+		return geom.MultiLineStringZ(p)
+	case geom.PolygonM:
+		return geom.MultiLineStringM(p)
+	case geom.PolygonZM:
+		return geom.MultiLineStringZM(p)
+	case geom.Polygon:
+		return geom.MultiLineString(p)
+	}
+	return nil
+}
+
+func multiPolygon(c [][][][]float64, d dim) geom.Geometry {
+	switch d {
+	case dimXYZ:
+		mp := make(geom.MultiPolygonZ, len(c))
+		for i := range c {
+			mp[i] = polygon(c[i], d).(geom.PolygonZ)
+		}
+		return mp
+	case dimXYM:
+		mp := make(geom.MultiPolygonM, len(c))
+		for i := range c {
+			mp[i] = polygon(c[i], d).(geom.PolygonM)
+		}
+		return mp
+	case dimXYZM:
+		mp := make(geom.MultiPolygonZM, len(c))
+		for i := range c {
+			mp[i] = polygon(c[i], d).(geom.PolygonZM)
+		}
+		return mp
+	}
+	mp := make(geom.MultiPolygon, len(c))
+	for i := range c {
+		mp[i] = polygon(c[i], d).(geom.Polygon)
+	}
+	return mp
+}
+
+//geometryCollection builds a collection of the given dimension.
+//If the dimension is unknown, the largest dimension shared by all children is used.
+func geometryCollection(geoms []geom.Geometry, d dim) (geom.Geometry, error) {
+	if d == dimUnknown && len(geoms) > 0 {
+		d = dimXYZM
+		for _, g := range geoms {
+			_, isZ := g.(geom.GeometryZ)
+			_, isM := g.(geom.GeometryM)
+			_, isZM := g.(geom.GeometryZM)
+			switch {
+			case isZM:
+			case isZ && (d == dimXYZ || d == dimXYZM):
+				d = dimXYZ
+			case isM && (d == dimXYM || d == dimXYZM):
+				d = dimXYM
+			default:
+				d = dimXY
+			}
+		}
+	}
+
+	var ok bool
+	switch d {
+	case dimXYZ:
+		c := make(geom.GeometryCollectionZ, len(geoms))
+		for i, g := range geoms {
+			if c[i], ok = g.(geom.GeometryZ); !ok {
+				return nil, fmt.Errorf("Unexpected child geometry type in GeometryCollectionZ: %s", reflect.TypeOf(g).String())
+			}
+		}
+		return c, nil
+	case dimXYM:
+		c := make(geom.GeometryCollectionM, len(geoms))
+		for i, g := range geoms {
+			if c[i], ok = g.(geom.GeometryM); !ok {
+				return nil, fmt.Errorf("Unexpected child geometry type in GeometryCollectionM: %s", reflect.TypeOf(g).String())
+			}
+		}
+		return c, nil
+	case dimXYZM:
+		c := make(geom.GeometryCollectionZM, len(geoms))
+		for i, g := range geoms {
+			if c[i], ok = g.(geom.GeometryZM); !ok {
+				return nil, fmt.Errorf("Unexpected child geometry type in GeometryCollectionZM: %s", reflect.TypeOf(g).String())
+			}
+		}
+		return c, nil
+	}
+	return geom.GeometryCollection(geoms), nil
+}
+
+//Write writes the WKT representation of g
+func Write(w io.Writer, g geom.Geometry) error {
+	s, err := Format(g)
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(w, s)
+	return err
+}
+
+//Format returns the WKT representation of g.
+//It returns an error if the geometry type is not supported.
+func Format(g geom.Geometry) (string, error) {
+	var b bytes.Buffer
+	if err := writeGeometry(&b, g, true); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+func writeFloat(b *bytes.Buffer, v float64) {
+	b.WriteString(strconv.FormatFloat(v, 'f', -1, 64))
+}
+
+//writePosition writes the values of a coordinate
+func writePosition(b *bytes.Buffer, values ...float64) {
+	for i, v := range values {
+		if i > 0 {
+			b.WriteString(" ")
+		}
+		writeFloat(b, v)
+	}
+}
+
+//writeList writes n positions, parenthesized, or EMPTY if n is 0
+func writeList(b *bytes.Buffer, n int, position func(i int)) {
+	if n == 0 {
+		b.WriteString("EMPTY")
+		return
+	}
+	b.WriteString("(")
+	for i := 0; i < n; i++ {
+		if i > 0 {
+			b.WriteString(",")
+		}
+		position(i)
+	}
+	b.WriteString(")")
+}
+
+//writeHeader writes the type name and its dimension tag (only for top-level geometries)
+func writeHeader(b *bytes.Buffer, name string, tag string, top bool) {
+	b.WriteString(name)
+	if tag != "" && top {
+		b.WriteString(" " + tag)
+	}
+	b.WriteString(" ")
+}
+
+func writeGeometry(b *bytes.Buffer, g geom.Geometry, top bool) error {
+	switch g := g.(type) {
+	/* Point */
+	case *geom.Point:
+		writeHeader(b, "POINT", "", top)
+		if math.IsNaN(g.X) && math.IsNaN(g.Y) {
+			b.WriteString("EMPTY")
+			return nil
+		}
+		writeList(b, 1, func(int) { writePosition(b, g.X, g.Y) })
+	case *geom.PointZ:
+		writeHeader(b, "POINT", "Z", top)
+		if math.IsNaN(g.X) && math.IsNaN(g.Y) {
+			b.WriteString("EMPTY")
+			return nil
+		}
+		writeList(b, 1, func(int) { writePosition(b, g.X, g.Y, g.Z) })
+	case *geom.PointM:
+		writeHeader(b, "POINT", "M", top)
+		if math.IsNaN(g.X) && math.IsNaN(g.Y) {
+			b.WriteString("EMPTY")
+			return nil
+		}
+		writeList(b, 1, func(int) { writePosition(b, g.X, g.Y, g.M) })
+	case *geom.PointZM:
+		writeHeader(b, "POINT", "ZM", top)
+		if math.IsNaN(g.X) && math.IsNaN(g.Y) {
+			b.WriteString("EMPTY")
+			return nil
+		}
+		writeList(b, 1, func(int) { writePosition(b, g.X, g.Y, g.Z, g.M) })
+	/* LineString */
+	case geom.LineString:
+		writeHeader(b, "LINESTRING", "", top)
+		writeList(b, len(g), func(i int) { writePosition(b, g[i].X, g[i].Y) })
+	case geom.LineStringZ:
+		writeHeader(b, "LINESTRING", "Z", top)
+		writeList(b, len(g), func(i int) { writePosition(b, g[i].X, g[i].Y, g[i].Z) })
+	case geom.LineStringM:
+		writeHeader(b, "LINESTRING", "M", top)
+		writeList(b, len(g), func(i int) { writePosition(b, g[i].X, g[i].Y, g[i].M) })
+	case geom.LineStringZM:
+		writeHeader(b, "LINESTRING", "ZM", top)
+		writeList(b, len(g), func(i int) { writePosition(b, g[i].X, g[i].Y, g[i].Z, g[i].M) })
+	/* Polygon */
+	case geom.Polygon:
+		writeHeader(b, "POLYGON", "", top)
+		writeList(b, len(g), func(r int) {
+			writeList(b, len(g[r]), func(i int) { writePosition(b, g[r][i].X, g[r][i].Y) })
+		})
+	case geom.PolygonZ:
+		writeHeader(b, "POLYGON", "Z", top)
+		writeList(b, len(g), func(r int) {
+			writeList(b, len(g[r]), func(i int) { writePosition(b, g[r][i].X, g[r][i].Y, g[r][i].Z) })
+		})
+	case geom.PolygonM:
+		writeHeader(b, "POLYGON", "M", top)
+		writeList(b, len(g), func(r int) {
+			writeList(b, len(g[r]), func(i int) { writePosition(b, g[r][i].X, g[r][i].Y, g[r][i].M) })
+		})
+	case geom.PolygonZM:
+		writeHeader(b, "POLYGON", "ZM", top)
+		writeList(b, len(g), func(r int) {
+			writeList(b, len(g[r]), func(i int) { writePosition(b, g[r][i].X, g[r][i].Y, g[r][i].Z, g[r][i].M) })
+		})
+	/* MultiPoint */
+	case geom.MultiPoint:
+		writeHeader(b, "MULTIPOINT", "", top)
+		writeList(b, len(g), func(i int) { writeList(b, 1, func(int) { writePosition(b, g[i].X, g[i].Y) }) })
+	case geom.MultiPointZ:
+		writeHeader(b, "MULTIPOINT", "Z", top)
+		writeList(b, len(g), func(i int) { writeList(b, 1, func(int) { writePosition(b, g[i].X, g[i].Y, g[i].Z) }) })
+	case geom.MultiPointM:
+		writeHeader(b, "MULTIPOINT", "M", top)
+		writeList(b, len(g), func(i int) { writeList(b, 1, func(int) { writePosition(b, g[i].X, g[i].Y, g[i].M) }) })
+	case geom.MultiPointZM:
+		writeHeader(b, "MULTIPOINT", "ZM", top)
+		writeList(b, len(g), func(i int) { writeList(b, 1, func(int) { writePosition(b, g[i].X, g[i].Y, g[i].Z, g[i].M) }) })
+	/* MultiLineString */
+	case geom.MultiLineString:
+		writeHeader(b, "MULTILINESTRING", "", top)
+		writeList(b, len(g), func(l int) {
+			writeList(b, len(g[l]), func(i int) { writePosition(b, g[l][i].X, g[l][i].Y) })
+		})
+	case geom.MultiLineStringZ:
+		writeHeader(b, "MULTILINESTRING", "Z", top)
+		writeList(b, len(g), func(l int) {
+			writeList(b, len(g[l]), func(i int) { writePosition(b, g[l][i].X, g[l][i].Y, g[l][i].Z) })
+		})
+	case geom.MultiLineStringM:
+		writeHeader(b, "MULTILINESTRING", "M", top)
+		writeList(b, len(g), func(l int) {
+			writeList(b, len(g[l]), func(i int) { writePosition(b, g[l][i].X, g[l][i].Y, g[l][i].M) })
+		})
+	case geom.MultiLineStringZM:
+		writeHeader(b, "MULTILINESTRING", "ZM", top)
+		writeList(b, len(g), func(l int) {
+			writeList(b, len(g[l]), func(i int) { writePosition(b, g[l][i].X, g[l][i].Y, g[l][i].Z, g[l][i].M) })
+		})
+	/* MultiPolygon */
+	case geom.MultiPolygon:
+		writeHeader(b, "MULTIPOLYGON", "", top)
+		writeList(b, len(g), func(p int) {
+			writeList(b, len(g[p]), func(r int) {
+				writeList(b, len(g[p][r]), func(i int) { writePosition(b, g[p][r][i].X, g[p][r][i].Y) })
+			})
+		})
+	case geom.MultiPolygonZ:
+		writeHeader(b, "MULTIPOLYGON", "Z", top)
+		writeList(b, len(g), func(p int) {
+			writeList(b, len(g[p]), func(r int) {
+				writeList(b, len(g[p][r]), func(i int) { writePosition(b, g[p][r][i].X, g[p][r][i].Y, g[p][r][i].Z) })
+			})
+		})
+	case geom.MultiPolygonM:
+		writeHeader(b, "MULTIPOLYGON", "M", top)
+		writeList(b, len(g), func(p int) {
+			writeList(b, len(g[p]), func(r int) {
+				writeList(b, len(g[p][r]), func(i int) { writePosition(b, g[p][r][i].X, g[p][r][i].Y, g[p][r][i].M) })
+			})
+		})
+	case geom.MultiPolygonZM:
+		writeHeader(b, "MULTIPOLYGON", "ZM", top)
+		writeList(b, len(g), func(p int) {
+			writeList(b, len(g[p]), func(r int) {
+				writeList(b, len(g[p][r]), func(i int) { writePosition(b, g[p][r][i].X, g[p][r][i].Y, g[p][r][i].Z, g[p][r][i].M) })
+			})
+		})
+	/* GeometryCollection */
+	case geom.GeometryCollection:
+		writeHeader(b, "GEOMETRYCOLLECTION", "", top)
+		return writeCollection(b, len(g), func(i int) geom.Geometry { return g[i] }, top)
+	case geom.GeometryCollectionZ:
+		writeHeader(b, "GEOMETRYCOLLECTION", "Z", top)
+		return writeCollection(b, len(g), func(i int) geom.Geometry { return g[i] }, false)
+	case geom.GeometryCollectionM:
+		writeHeader(b, "GEOMETRYCOLLECTION", "M", top)
+		return writeCollection(b, len(g), func(i int) geom.Geometry { return g[i] }, false)
+	case geom.GeometryCollectionZM:
+		writeHeader(b, "GEOMETRYCOLLECTION", "ZM", top)
+		return writeCollection(b, len(g), func(i int) geom.Geometry { return g[i] }, false)
+	default:
+		return fmt.Errorf("Unsupported geometry type: %v", reflect.TypeOf(g))
+	}
+	return nil
+}
+
+//writeCollection writes the children of a collection.
+//Children only carry their own dimension tag if the collection does not have one.
+func writeCollection(b *bytes.Buffer, n int, child func(i int) geom.Geometry, tagged bool) error {
+	var err error
+	writeList(b, n, func(i int) {
+		if err == nil {
+			err = writeGeometry(b, child(i), tagged)
+		}
+	})
+	return err
+}
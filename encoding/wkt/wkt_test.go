@@ -0,0 +1,177 @@
+// Copyright 2015 Simon HEGE. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package wkt
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/xeonx/geom"
+)
+
+var roundTripTests = []struct {
+	wkt  string
+	want string //type of the parsed geometry
+}{
+	{"POINT (1 2)", "*geom.Point"},
+	{"POINT Z (1 2 3)", "*geom.PointZ"},
+	{"POINT M (1 2 3)", "*geom.PointM"},
+	{"POINT ZM (1 2 3 4)", "*geom.PointZM"},
+	{"POINT EMPTY", "*geom.Point"},
+	{"POINT Z EMPTY", "*geom.PointZ"},
+	{"POINT M EMPTY", "*geom.PointM"},
+	{"POINT ZM EMPTY", "*geom.PointZM"},
+
+	{"LINESTRING (1 2,3 4)", "geom.LineString"},
+	{"LINESTRING Z (1 2 3,4 5 6)", "geom.LineStringZ"},
+	{"LINESTRING M (1 2 3,4 5 6)", "geom.LineStringM"},
+	{"LINESTRING ZM (1 2 3 4,5 6 7 8)", "geom.LineStringZM"},
+	{"LINESTRING EMPTY", "geom.LineString"},
+	{"LINESTRING Z EMPTY", "geom.LineStringZ"},
+	{"LINESTRING M EMPTY", "geom.LineStringM"},
+	{"LINESTRING ZM EMPTY", "geom.LineStringZM"},
+
+	{"POLYGON ((0 0,1 0,1 1,0 0),(0.1 0.1,0.2 0.1,0.1 0.2,0.1 0.1))", "geom.Polygon"},
+	{"POLYGON Z ((0 0 1,1 0 1,1 1 1,0 0 1))", "geom.PolygonZ"},
+	{"POLYGON M ((0 0 1,1 0 1,1 1 1,0 0 1))", "geom.PolygonM"},
+	{"POLYGON ZM ((0 0 1 2,1 0 1 2,1 1 1 2,0 0 1 2))", "geom.PolygonZM"},
+	{"POLYGON EMPTY", "geom.Polygon"},
+	{"POLYGON Z EMPTY", "geom.PolygonZ"},
+	{"POLYGON M EMPTY", "geom.PolygonM"},
+	{"POLYGON ZM EMPTY", "geom.PolygonZM"},
+
+	{"MULTIPOINT ((1 2),(3 4))", "geom.MultiPoint"},
+	{"MULTIPOINT Z ((1 2 3))", "geom.MultiPointZ"},
+	{"MULTIPOINT M ((1 2 3))", "geom.MultiPointM"},
+	{"MULTIPOINT ZM ((1 2 3 4))", "geom.MultiPointZM"},
+	{"MULTIPOINT EMPTY", "geom.MultiPoint"},
+	{"MULTIPOINT Z EMPTY", "geom.MultiPointZ"},
+	{"MULTIPOINT M EMPTY", "geom.MultiPointM"},
+	{"MULTIPOINT ZM EMPTY", "geom.MultiPointZM"},
+
+	{"MULTILINESTRING ((1 2,3 4),(5 6,7 8))", "geom.MultiLineString"},
+	{"MULTILINESTRING Z ((1 2 3,4 5 6))", "geom.MultiLineStringZ"},
+	{"MULTILINESTRING M ((1 2 3,4 5 6))", "geom.MultiLineStringM"},
+	{"MULTILINESTRING ZM ((1 2 3 4,5 6 7 8))", "geom.MultiLineStringZM"},
+	{"MULTILINESTRING EMPTY", "geom.MultiLineString"},
+	{"MULTILINESTRING Z EMPTY", "geom.MultiLineStringZ"},
+	{"MULTILINESTRING M EMPTY", "geom.MultiLineStringM"},
+	{"MULTILINESTRING ZM EMPTY", "geom.MultiLineStringZM"},
+
+	{"MULTIPOLYGON (((0 0,1 0,1 1,0 0)),((5 5,6 5,6 6,5 5)))", "geom.MultiPolygon"},
+	{"MULTIPOLYGON Z (((0 0 1,1 0 1,1 1 1,0 0 1)))", "geom.MultiPolygonZ"},
+	{"MULTIPOLYGON M (((0 0 1,1 0 1,1 1 1,0 0 1)))", "geom.MultiPolygonM"},
+	{"MULTIPOLYGON ZM (((0 0 1 2,1 0 1 2,1 1 1 2,0 0 1 2)))", "geom.MultiPolygonZM"},
+	{"MULTIPOLYGON EMPTY", "geom.MultiPolygon"},
+	{"MULTIPOLYGON Z EMPTY", "geom.MultiPolygonZ"},
+	{"MULTIPOLYGON M EMPTY", "geom.MultiPolygonM"},
+	{"MULTIPOLYGON ZM EMPTY", "geom.MultiPolygonZM"},
+
+	{"GEOMETRYCOLLECTION (POINT (1 2),LINESTRING (1 2,3 4))", "geom.GeometryCollection"},
+	{"GEOMETRYCOLLECTION Z (POINT (1 2 3),LINESTRING (1 2 3,4 5 6))", "geom.GeometryCollectionZ"},
+	{"GEOMETRYCOLLECTION M (POINT (1 2 3))", "geom.GeometryCollectionM"},
+	{"GEOMETRYCOLLECTION ZM (POINT (1 2 3 4))", "geom.GeometryCollectionZM"},
+	{"GEOMETRYCOLLECTION EMPTY", "geom.GeometryCollection"},
+	{"GEOMETRYCOLLECTION (POINT EMPTY,GEOMETRYCOLLECTION EMPTY)", "geom.GeometryCollection"},
+}
+
+func TestRoundTrip(t *testing.T) {
+	for _, tt := range roundTripTests {
+		g, err := Parse(tt.wkt)
+		if err != nil {
+			t.Errorf("Parse(%q): %v", tt.wkt, err)
+			continue
+		}
+		if got := reflect.TypeOf(g).String(); got != tt.want {
+			t.Errorf("Parse(%q) type = %s, want %s", tt.wkt, got, tt.want)
+		}
+		s, err := Format(g)
+		if err != nil {
+			t.Errorf("Format(%q): %v", tt.wkt, err)
+			continue
+		}
+		if s != tt.wkt {
+			t.Errorf("Format(Parse(%q)) = %q", tt.wkt, s)
+		}
+	}
+}
+
+func TestReadWrite(t *testing.T) {
+	for _, tt := range roundTripTests {
+		var b bytes.Buffer
+		g, _ := Parse(tt.wkt)
+		if err := Write(&b, g); err != nil {
+			t.Errorf("Write(%q): %v", tt.wkt, err)
+			continue
+		}
+		r, err := Read(&b)
+		if err != nil {
+			t.Errorf("Read(%q): %v", tt.wkt, err)
+			continue
+		}
+		if g.IsEmpty() {
+			//Empty points have NaN coordinates
+			if !r.IsEmpty() || reflect.TypeOf(r) != reflect.TypeOf(g) {
+				t.Errorf("Read(Write(%q)) = %#v", tt.wkt, r)
+			}
+			continue
+		}
+		if !reflect.DeepEqual(r, g) {
+			t.Errorf("Read(Write(%q)) = %#v, want %#v", tt.wkt, r, g)
+		}
+	}
+}
+
+func TestParseVariants(t *testing.T) {
+	tests := []struct {
+		wkt  string
+		want geom.Geometry
+	}{
+		{"point(1 2)", &geom.Point{X: 1, Y: 2}},
+		{"POINTZ (1 2 3)", &geom.PointZ{Point: geom.Point{X: 1, Y: 2}, Z: 3}},
+		{"POINTM(1 2 3)", &geom.PointM{Point: geom.Point{X: 1, Y: 2}, M: 3}},
+		{"POINT (1 2 3)", &geom.PointZ{Point: geom.Point{X: 1, Y: 2}, Z: 3}},
+		{"POINT (1 2 3 4)", &geom.PointZM{PointZ: geom.PointZ{Point: geom.Point{X: 1, Y: 2}, Z: 3}, M: 4}},
+		{"  POINT ( -1.5e3  2 ) ", &geom.Point{X: -1500, Y: 2}},
+		{"MULTIPOINT (1 2, 3 4)", geom.MultiPoint{{X: 1, Y: 2}, {X: 3, Y: 4}}},
+		{"GEOMETRYCOLLECTION (POINT Z (1 2 3))", geom.GeometryCollectionZ{&geom.PointZ{Point: geom.Point{X: 1, Y: 2}, Z: 3}}},
+	}
+	for _, tt := range tests {
+		g, err := Parse(tt.wkt)
+		if err != nil {
+			t.Errorf("Parse(%q): %v", tt.wkt, err)
+			continue
+		}
+		if !reflect.DeepEqual(g, tt.want) {
+			t.Errorf("Parse(%q) = %#v, want %#v", tt.wkt, g, tt.want)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, s := range []string{
+		"",
+		"POINT (1)",
+		"POINT (1 2",
+		"POINT (1 2) x",
+		"POINT Z (1 2)",
+		"LINESTRING (1 2, 3 4 5)",
+		"POLYGON ((0 0, 1 0, 1 1, 0 0)",
+		"FOO (1 2)",
+	} {
+		if g, err := Parse(s); err == nil {
+			t.Errorf("Parse(%q) = %#v, want an error", s, g)
+		}
+	}
+}
+
+func TestFormatUnsupported(t *testing.T) {
+	for _, g := range []geom.Geometry{nil, geom.GeometryCollection{nil}} {
+		if _, err := Format(g); err == nil {
+			t.Errorf("Format(%#v) should fail", g)
+		}
+	}
+}
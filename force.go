@@ -0,0 +1,320 @@
+// Copyright 2015 Simon HEGE. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package geom
+
+import (
+	"fmt"
+	"reflect"
+)
+
+//Force2D returns a copy of the geometry without Z and M values.
+//Geometry collections are converted recursively.
+func Force2D(g Geometry) (Geometry, error) {
+	zm, err := ForceZM(g, 0, 0)
+	if err != nil {
+		return nil, err
+	}
+	return project2D(zm), nil
+}
+
+//ForceZ returns a copy of the geometry as a three-dimensional geometry, without M values.
+//Missing Z values are set to z. Geometry collections are converted recursively.
+func ForceZ(g Geometry, z float64) (GeometryZ, error) {
+	zm, err := ForceZM(g, z, 0)
+	if err != nil {
+		return nil, err
+	}
+	return projectZ(zm), nil
+}
+
+//ForceM returns a copy of the geometry as a two-dimensional geometry with M values.
+//Missing M values are set to m. Geometry collections are converted recursively.
+func ForceM(g Geometry, m float64) (GeometryM, error) {
+	zm, err := ForceZM(g, 0, m)
+	if err != nil {
+		return nil, err
+	}
+	return projectM(zm), nil
+}
+
+//ForceZM returns a copy of the geometry as a three-dimensional geometry with M values.
+//Missing Z and M values are set to z and m. Geometry collections are converted recursively.
+//Pointers to slice based geometries, as returned by Clone, are accepted; slice based results are returned by value.
+func ForceZM(g Geometry, z, m float64) (GeometryZM, error) {
+	//Pointers to slice based geometries, as returned by Clone
+	if v := reflect.ValueOf(g); v.Kind() == reflect.Ptr && !v.IsNil() && v.Elem().Kind() == reflect.Slice {
+		g = v.Elem().Interface().(Geometry)
+	}
+
+	switch g := g.(type) {
+	/* Point */
+	case *Point:
+		return &PointZM{PointZ: PointZ{Point: *g, Z: z}, M: m}, nil
+	case *PointZ:
+		return &PointZM{PointZ: *g, M: m}, nil
+	case *PointM:
+		return &PointZM{PointZ: PointZ{Point: g.Point, Z: z}, M: g.M}, nil
+	case *PointZM:
+		pt := *g
+		return &pt, nil
+	/* LineString */
+	case LineString, LineStringZ, LineStringM, LineStringZM:
+		return lineStringZM(g, z, m), nil
+	/* Polygon */
+	case Polygon, PolygonZ, PolygonM, PolygonZM:
+		return polygonZM(g, z, m), nil
+	/* MultiPoint */
+	case MultiPoint:
+		return MultiPointZM(lineStringZM(LineString(g), z, m)), nil
+	case MultiPointZ:
+		return MultiPointZM(lineStringZM(LineStringZ(g), z, m)), nil
+	case MultiPointM:
+		return MultiPointZM(lineStringZM(LineStringM(g), z, m)), nil
+	case MultiPointZM:
+		return MultiPointZM(lineStringZM(LineStringZM(g), z, m)), nil
+	/* MultiLineString */
+	case MultiLineString:
+		return MultiLineStringZM(polygonZM(Polygon(g), z, m)), nil
+	case MultiLineStringZ:
+		return MultiLineStringZM(polygonZM(PolygonZ(g), z, m)), nil
+	case MultiLineStringM:
+		return MultiLineStringZM(polygonZM(PolygonM(g), z, m)), nil
+	case MultiLineStringZM:
+		return MultiLineStringZM(polygonZM(PolygonZM(g), z, m)), nil
+	/* MultiPolygon */
+	case MultiPolygon:
+		mp := make(MultiPolygonZM, len(g))
+		for i := range g {
+			mp[i] = polygonZM(g[i], z, m)
+		}
+		return mp, nil
+	case MultiPolygonZ:
+		mp := make(MultiPolygonZM, len(g))
+		for i := range g {
+			mp[i] = polygonZM(g[i], z, m)
+		}
+		return mp, nil
+	case MultiPolygonM:
+		mp := make(MultiPolygonZM, len(g))
+		for i := range g {
+			mp[i] = polygonZM(g[i], z, m)
+		}
+		return mp, nil
+	case MultiPolygonZM:
+		mp := make(MultiPolygonZM, len(g))
+		for i := range g {
+			mp[i] = polygonZM(g[i], z, m)
+		}
+		return mp, nil
+	/* GeometryCollection */
+	case GeometryCollection:
+		return geometryCollectionZM(len(g), func(i int) Geometry { return g[i] }, z, m)
+	case GeometryCollectionZ:
+		return geometryCollectionZM(len(g), func(i int) Geometry { return g[i] }, z, m)
+	case GeometryCollectionM:
+		return geometryCollectionZM(len(g), func(i int) Geometry { return g[i] }, z, m)
+	case GeometryCollectionZM:
+		return geometryCollectionZM(len(g), func(i int) Geometry { return g[i] }, z, m)
+	}
+
+	return nil, fmt.Errorf("Unsupported geometry type: %v", reflect.TypeOf(g))
+}
+
+func lineStringZM(g Geometry, z, m float64) LineStringZM {
+	var l LineStringZM
+	switch g := g.(type) {
+	case LineString:
+		l = make(LineStringZM, len(g))
+		for i, pt := range g {
+			l[i] = PointZM{PointZ: PointZ{Point: pt, Z: z}, M: m}
+		}
+	case LineStringZ:
+		l = make(LineStringZM, len(g))
+		for i, pt := range g {
+			l[i] = PointZM{PointZ: pt, M: m}
+		}
+	case LineStringM:
+		l = make(LineStringZM, len(g))
+		for i, pt := range g {
+			l[i] = PointZM{PointZ: PointZ{Point: pt.Point, Z: z}, M: pt.M}
+		}
+	case LineStringZM:
+		l = make(LineStringZM, len(g))
+		copy(l, g)
+	}
+	return l
+}
+
+func polygonZM(g Geometry, z, m float64) PolygonZM {
+	var p PolygonZM
+	switch g := g.(type) {
+	case Polygon:
+		p = make(PolygonZM, len(g))
+		for i := range g {
+			p[i] = lineStringZM(g[i], z, m)
+		}
+	case PolygonZ:
+		p = make(PolygonZM, len(g))
+		for i := range g {
+			p[i] = lineStringZM(g[i], z, m)
+		}
+	case PolygonM:
+		p = make(PolygonZM, len(g))
+		for i := range g {
+			p[i] = lineStringZM(g[i], z, m)
+		}
+	case PolygonZM:
+		p = make(PolygonZM, len(g))
+		for i := range g {
+			p[i] = lineStringZM(g[i], z, m)
+		}
+	}
+	return p
+}
+
+func geometryCollectionZM(n int, child func(i int) Geometry, z, m float64) (GeometryCollectionZM, error) {
+	c := make(GeometryCollectionZM, n)
+	for i := range c {
+		var err error
+		if c[i], err = ForceZM(child(i), z, m); err != nil {
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+//project2D converts a geometry returned by ForceZM to its two-dimensional variant
+func project2D(g GeometryZM) Geometry {
+	line := func(l LineStringZM) LineString {
+		r := make(LineString, len(l))
+		for i := range l {
+			r[i] = l[i].Point
+		}
+		return r
+	}
+	polygon := func(p PolygonZM) Polygon {
+		r := make(Polygon, len(p))
+		for i := range p {
+			r[i] = line(p[i])
+		}
+		return r
+	}
+
+	switch g := g.(type) {
+	case *PointZM:
+		pt := g.Point
+		return &pt
+	case LineStringZM:
+		return line(g)
+	case PolygonZM:
+		return polygon(g)
+	case MultiPointZM:
+		return MultiPoint(line(LineStringZM(g)))
+	case MultiLineStringZM:
+		return MultiLineString(polygon(PolygonZM(g)))
+	case MultiPolygonZM:
+		r := make(MultiPolygon, len(g))
+		for i := range g {
+			r[i] = polygon(g[i])
+		}
+		return r
+	case GeometryCollectionZM:
+		r := make(GeometryCollection, len(g))
+		for i := range g {
+			r[i] = project2D(g[i])
+		}
+		return r
+	}
+	return nil
+}
+
+//projectZ converts a geometry returned by ForceZM to its Z variant
+func projectZ(g GeometryZM) GeometryZ {
+	line := func(l LineStringZM) LineStringZ {
+		r := make(LineStringZ, len(l))
+		for i := range l {
+			r[i] = l[i].PointZ
+		}
+		return r
+	}
+	polygon := func(p PolygonZM) PolygonZ {
+		r := make(PolygonZ, len(p))
+		for i := range p {
+			r[i] = line(p[i])
+		}
+		return r
+	}
+
+	switch g := g.(type) {
+	case *PointZM:
+		pt := g.PointZ
+		return &pt
+	case LineStringZM:
+		return line(g)
+	case PolygonZM:
+		return polygon(g)
+	case MultiPointZM:
+		return MultiPointZ(line(LineStringZM(g)))
+	case MultiLineStringZM:
+		return MultiLineStringZ(polygon(PolygonZM(g)))
+	case MultiPolygonZM:
+		r := make(MultiPolygonZ, len(g))
+		for i := range g {
+			r[i] = polygon(g[i])
+		}
+		return r
+	case GeometryCollectionZM:
+		r := make(GeometryCollectionZ, len(g))
+		for i := range g {
+			r[i] = projectZ(g[i])
+		}
+		return r
+	}
+	return nil
+}
+
+//projectM converts a geometry returned by ForceZM to its M variant
+func projectM(g GeometryZM) GeometryM {
+	line := func(l LineStringZM) LineStringM {
+		r := make(LineStringM, len(l))
+		for i := range l {
+			r[i] = PointM{Point: l[i].Point, M: l[i].M}
+		}
+		return r
+	}
+	polygon := func(p PolygonZM) PolygonM {
+		r := make(PolygonM, len(p))
+		for i := range p {
+			r[i] = line(p[i])
+		}
+		return r
+	}
+
+	switch g := g.(type) {
+	case *PointZM:
+		return &PointM{Point: g.Point, M: g.M}
+	case LineStringZM:
+		return line(g)
+	case PolygonZM:
+		return polygon(g)
+	case MultiPointZM:
+		return MultiPointM(line(LineStringZM(g)))
+	case MultiLineStringZM:
+		return MultiLineStringM(polygon(PolygonZM(g)))
+	case MultiPolygonZM:
+		r := make(MultiPolygonM, len(g))
+		for i := range g {
+			r[i] = polygon(g[i])
+		}
+		return r
+	case GeometryCollectionZM:
+		r := make(GeometryCollectionM, len(g))
+		for i := range g {
+			r[i] = projectM(g[i])
+		}
+		return r
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+// Copyright 2015 Simon HEGE. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package geom
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestForce(t *testing.T) {
+	pt := Point{X: 1, Y: 2}
+	ptZ := PointZ{Point: pt, Z: 3}
+	ptM := PointM{Point: pt, M: 4}
+	ptZM := PointZM{PointZ: ptZ, M: 4}
+
+	tests := []struct {
+		g             Geometry
+		want2D, wantZ Geometry
+		wantM, wantZM Geometry
+	}{
+		{g: &pt, want2D: &pt, wantZ: &PointZ{Point: pt, Z: 9}, wantM: &PointM{Point: pt, M: 8}, wantZM: &PointZM{PointZ: PointZ{Point: pt, Z: 9}, M: 8}},
+		{g: &ptZ, want2D: &pt, wantZ: &ptZ, wantM: &PointM{Point: pt, M: 8}, wantZM: &PointZM{PointZ: ptZ, M: 8}},
+		{g: &ptM, want2D: &pt, wantZ: &PointZ{Point: pt, Z: 9}, wantM: &ptM, wantZM: &PointZM{PointZ: PointZ{Point: pt, Z: 9}, M: 4}},
+		{g: &ptZM, want2D: &pt, wantZ: &ptZ, wantM: &ptM, wantZM: &ptZM},
+		{g: LineStringZ{ptZ}, want2D: LineString{pt}, wantZ: LineStringZ{ptZ}, wantM: LineStringM{{Point: pt, M: 8}}, wantZM: LineStringZM{{PointZ: ptZ, M: 8}}},
+		{g: PolygonM{{ptM}}, want2D: Polygon{{pt}}, wantZ: PolygonZ{{{Point: pt, Z: 9}}}, wantM: PolygonM{{ptM}}, wantZM: PolygonZM{{{PointZ: PointZ{Point: pt, Z: 9}, M: 4}}}},
+		{g: MultiPoint{pt}, want2D: MultiPoint{pt}, wantZ: MultiPointZ{{Point: pt, Z: 9}}, wantM: MultiPointM{{Point: pt, M: 8}}, wantZM: MultiPointZM{{PointZ: PointZ{Point: pt, Z: 9}, M: 8}}},
+		{g: MultiLineStringZM{{ptZM}}, want2D: MultiLineString{{pt}}, wantZ: MultiLineStringZ{{ptZ}}, wantM: MultiLineStringM{{ptM}}, wantZM: MultiLineStringZM{{ptZM}}},
+		{g: MultiPolygonZ{{{ptZ}}}, want2D: MultiPolygon{{{pt}}}, wantZ: MultiPolygonZ{{{ptZ}}}, wantM: MultiPolygonM{{{{Point: pt, M: 8}}}}, wantZM: MultiPolygonZM{{{{PointZ: ptZ, M: 8}}}}},
+		{g: GeometryCollection{&pt, LineString{pt}}, want2D: GeometryCollection{&pt, LineString{pt}},
+			wantZ:  GeometryCollectionZ{&PointZ{Point: pt, Z: 9}, LineStringZ{{Point: pt, Z: 9}}},
+			wantM:  GeometryCollectionM{&PointM{Point: pt, M: 8}, LineStringM{{Point: pt, M: 8}}},
+			wantZM: GeometryCollectionZM{&PointZM{PointZ: PointZ{Point: pt, Z: 9}, M: 8}, LineStringZM{{PointZ: PointZ{Point: pt, Z: 9}, M: 8}}}},
+	}
+	for _, tt := range tests {
+		//The geometry itself, and its clone which is a pointer for slice based geometries
+		for _, g := range []Geometry{tt.g, tt.g.Clone()} {
+			if got, err := Force2D(g); err != nil || !reflect.DeepEqual(got, tt.want2D) {
+				t.Errorf("Force2D(%#v) = %#v, %v, want %#v", g, got, err, tt.want2D)
+			}
+			if got, err := ForceZ(g, 9); err != nil || !reflect.DeepEqual(got, tt.wantZ) {
+				t.Errorf("ForceZ(%#v) = %#v, %v, want %#v", g, got, err, tt.wantZ)
+			}
+			if got, err := ForceM(g, 8); err != nil || !reflect.DeepEqual(got, tt.wantM) {
+				t.Errorf("ForceM(%#v) = %#v, %v, want %#v", g, got, err, tt.wantM)
+			}
+			if got, err := ForceZM(g, 9, 8); err != nil || !reflect.DeepEqual(got, tt.wantZM) {
+				t.Errorf("ForceZM(%#v) = %#v, %v, want %#v", g, got, err, tt.wantZM)
+			}
+		}
+	}
+}
+
+func TestForceCopies(t *testing.T) {
+	l := LineString{{X: 1, Y: 2}, {X: 3, Y: 4}}
+	g, err := Force2D(l.Clone())
+	if err != nil {
+		t.Fatal(err)
+	}
+	g.(LineString)[0].X = 10
+	if l[0].X != 1 {
+		t.Error("Force2D does not copy the points")
+	}
+}
+
+func TestForceUnsupported(t *testing.T) {
+	var nilLine *LineString
+	for _, g := range []Geometry{nil, nilLine, GeometryCollection{nil}} {
+		if _, err := ForceZM(g, 0, 0); err == nil {
+			t.Errorf("ForceZM(%#v) should fail", g)
+		}
+	}
+}
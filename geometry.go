@@ -18,6 +18,33 @@ type Geometry interface {
 	Clone() Geometry
 
 	Iterate(f func([]Point) error) error //The iterate function can modify Point in place
+
+	GeometryType() string     //Name of the geometry type, as defined by OGC Simple Features (Point, LineString, ...)
+	Dimension() int           //Topological dimension: 0 for points, 1 for lines, 2 for surfaces, -1 for collections without geometry
+	CoordinateDimension() int //Number of values of each coordinate: 2, 3 or 4
+	IsEmpty() bool
+	NumPoints() int
+	NumGeometries() int
+	GeometryN(n int) Geometry //Zero based; returns nil if n is out of range
+}
+
+//Curve represents a one-dimensional geometry
+type Curve interface {
+	Geometry
+
+	StartPoint() Geometry
+	EndPoint() Geometry
+	IsClosed() bool
+	IsRing() bool
+}
+
+//Surface represents a two-dimensional geometry
+type Surface interface {
+	Geometry
+
+	ExteriorRing() Curve
+	NumInteriorRings() int
+	InteriorRingN(n int) Curve //Zero based; returns nil if n is out of range
 }
 
 //GeometryZ represents a three-dimensional geometry object
@@ -25,6 +52,8 @@ type GeometryZ interface {
 	Geometry
 
 	EnvelopeZ() *EnvelopeZ
+
+	IterateZ(f func([]PointZ) error) error //The iterate function can modify PointZ in place
 }
 
 //GeometryM represents a geometry object, with an additional value defined on each vertex
@@ -32,6 +61,8 @@ type GeometryM interface {
 	Geometry
 
 	EnvelopeM() *EnvelopeM
+
+	IterateM(f func([]PointM) error) error //The iterate function can modify PointM in place
 }
 
 //GeometryZM represents a three-dimensional geometry object, with an additional value defined on each vertex
@@ -40,8 +71,76 @@ type GeometryZM interface {
 
 	//Duplication of methods of GeometryM because we can not embed it directly (kind of diamond inheritance problem)
 	EnvelopeM() *EnvelopeM
+	IterateM(f func([]PointM) error) error
 
 	EnvelopeZM() *EnvelopeZM
+
+	IterateZM(f func([]PointZM) error) error //The iterate function can modify PointZM in place
+}
+
+//cloneGeometry returns a deep copy of g, of the same dynamic type: unlike Clone, slice based geometries
+//are returned by value
+func cloneGeometry(g Geometry) Geometry {
+	switch g := g.(type) {
+	case nil:
+		return nil
+	/* LineString */
+	case LineString:
+		return g.clone()
+	case LineStringZ:
+		return g.clone()
+	case LineStringM:
+		return g.clone()
+	case LineStringZM:
+		return g.clone()
+	/* Polygon */
+	case Polygon:
+		return g.clone()
+	case PolygonZ:
+		return g.clone()
+	case PolygonM:
+		return g.clone()
+	case PolygonZM:
+		return g.clone()
+	/* MultiPoint */
+	case MultiPoint:
+		return g.clone()
+	case MultiPointZ:
+		return g.clone()
+	case MultiPointM:
+		return g.clone()
+	case MultiPointZM:
+		return g.clone()
+	/* MultiLineString */
+	case MultiLineString:
+		return g.clone()
+	case MultiLineStringZ:
+		return g.clone()
+	case MultiLineStringM:
+		return g.clone()
+	case MultiLineStringZM:
+		return g.clone()
+	/* MultiPolygon */
+	case MultiPolygon:
+		return g.clone()
+	case MultiPolygonZ:
+		return g.clone()
+	case MultiPolygonM:
+		return g.clone()
+	case MultiPolygonZM:
+		return g.clone()
+	/* GeometryCollection */
+	case GeometryCollection:
+		return g.clone()
+	case GeometryCollectionZ:
+		return g.clone()
+	case GeometryCollectionM:
+		return g.clone()
+	case GeometryCollectionZM:
+		return g.clone()
+	}
+	//Points and pointers to slice based geometries: Clone returns a pointer of the same type
+	return g.Clone()
 }
 
 //Ensure that geometry structs implements the geometry interfaces
@@ -79,3 +178,13 @@ var _ Geometry = &GeometryCollection{}
 var _ GeometryZ = &GeometryCollectionZ{}
 var _ GeometryM = &GeometryCollectionM{}
 var _ GeometryZM = &GeometryCollectionZM{}
+
+var _ Curve = &LineString{}
+var _ Curve = &LineStringZ{}
+var _ Curve = &LineStringM{}
+var _ Curve = &LineStringZM{}
+
+var _ Surface = &Polygon{}
+var _ Surface = &PolygonZ{}
+var _ Surface = &PolygonM{}
+var _ Surface = &PolygonZM{}
@@ -99,22 +99,62 @@ func (c GeometryCollectionZM) EnvelopeZM() *EnvelopeZM {
 
 //Clone returns a deep copy of the geometry collection
 func (c GeometryCollection) Clone() Geometry {
-	return &c
+	clone := c.clone()
+	return &clone
+}
+
+//clone returns a deep copy of the geometry collection, by value
+func (c GeometryCollection) clone() GeometryCollection {
+	clone := make(GeometryCollection, len(c))
+	for i := range c {
+		clone[i] = cloneGeometry(c[i])
+	}
+	return clone
 }
 
 //Clone returns a deep copy of the geometry collection
 func (c GeometryCollectionZ) Clone() Geometry {
-	return &c
+	clone := c.clone()
+	return &clone
+}
+
+//clone returns a deep copy of the geometry collection, by value
+func (c GeometryCollectionZ) clone() GeometryCollectionZ {
+	clone := make(GeometryCollectionZ, len(c))
+	for i := range c {
+		clone[i] = cloneGeometry(c[i]).(GeometryZ)
+	}
+	return clone
 }
 
 //Clone returns a deep copy of the geometry collection
 func (c GeometryCollectionM) Clone() Geometry {
-	return &c
+	clone := c.clone()
+	return &clone
+}
+
+//clone returns a deep copy of the geometry collection, by value
+func (c GeometryCollectionM) clone() GeometryCollectionM {
+	clone := make(GeometryCollectionM, len(c))
+	for i := range c {
+		clone[i] = cloneGeometry(c[i]).(GeometryM)
+	}
+	return clone
 }
 
 //Clone returns a deep copy of the geometry collection
 func (c GeometryCollectionZM) Clone() Geometry {
-	return &c
+	clone := c.clone()
+	return &clone
+}
+
+//clone returns a deep copy of the geometry collection, by value
+func (c GeometryCollectionZM) clone() GeometryCollectionZM {
+	clone := make(GeometryCollectionZM, len(c))
+	for i := range c {
+		clone[i] = cloneGeometry(c[i]).(GeometryZM)
+	}
+	return clone
 }
 
 //Iterate walks over the points (and can modify in situ) the geometry collection
@@ -156,3 +196,269 @@ func (c GeometryCollectionZM) Iterate(f func([]Point) error) error {
 	}
 	return nil
 }
+
+//IterateZ walks over the points (and can modify in situ) the geometry collection
+func (c GeometryCollectionZ) IterateZ(f func([]PointZ) error) error {
+	for i := range c {
+		if err := c[i].IterateZ(f); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//IterateM walks over the points (and can modify in situ) the geometry collection
+func (c GeometryCollectionM) IterateM(f func([]PointM) error) error {
+	for i := range c {
+		if err := c[i].IterateM(f); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//IterateZ walks over the points (and can modify in situ) the geometry collection
+func (c GeometryCollectionZM) IterateZ(f func([]PointZ) error) error {
+	for i := range c {
+		if err := c[i].IterateZ(f); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//IterateM walks over the points (and can modify in situ) the geometry collection
+func (c GeometryCollectionZM) IterateM(f func([]PointM) error) error {
+	for i := range c {
+		if err := c[i].IterateM(f); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//IterateZM walks over the points (and can modify in situ) the geometry collection
+func (c GeometryCollectionZM) IterateZM(f func([]PointZM) error) error {
+	for i := range c {
+		if err := c[i].IterateZM(f); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//GeometryType returns the name of the geometry type
+func (c GeometryCollection) GeometryType() string {
+	return "GeometryCollection"
+}
+
+//Dimension returns the topological dimension of the geometry collection: the highest dimension of its geometries,
+//or -1 if it has no geometry
+func (c GeometryCollection) Dimension() int {
+	d := -1
+	for _, g := range c {
+		if g.Dimension() > d {
+			d = g.Dimension()
+		}
+	}
+	return d
+}
+
+//CoordinateDimension returns the number of values of each coordinate
+func (c GeometryCollection) CoordinateDimension() int {
+	return 2
+}
+
+//IsEmpty returns true if the geometry collection has no non-empty geometry
+func (c GeometryCollection) IsEmpty() bool {
+	for _, g := range c {
+		if !g.IsEmpty() {
+			return false
+		}
+	}
+	return true
+}
+
+//NumPoints returns the number of points of the geometry collection
+func (c GeometryCollection) NumPoints() int {
+	n := 0
+	for _, g := range c {
+		n += g.NumPoints()
+	}
+	return n
+}
+
+//NumGeometries returns the number of geometries
+func (c GeometryCollection) NumGeometries() int {
+	return len(c)
+}
+
+//GeometryN returns the n-th (zero based) geometry, or nil if n is out of range
+func (c GeometryCollection) GeometryN(n int) Geometry {
+	if n < 0 || n >= len(c) {
+		return nil
+	}
+	return c[n]
+}
+
+//GeometryType returns the name of the geometry type
+func (c GeometryCollectionZ) GeometryType() string {
+	return "GeometryCollection"
+}
+
+//Dimension returns the topological dimension of the geometry collection: the highest dimension of its geometries,
+//or -1 if it has no geometry
+func (c GeometryCollectionZ) Dimension() int {
+	d := -1
+	for _, g := range c {
+		if g.Dimension() > d {
+			d = g.Dimension()
+		}
+	}
+	return d
+}
+
+//CoordinateDimension returns the number of values of each coordinate
+func (c GeometryCollectionZ) CoordinateDimension() int {
+	return 3
+}
+
+//IsEmpty returns true if the geometry collection has no non-empty geometry
+func (c GeometryCollectionZ) IsEmpty() bool {
+	for _, g := range c {
+		if !g.IsEmpty() {
+			return false
+		}
+	}
+	return true
+}
+
+//NumPoints returns the number of points of the geometry collection
+func (c GeometryCollectionZ) NumPoints() int {
+	n := 0
+	for _, g := range c {
+		n += g.NumPoints()
+	}
+	return n
+}
+
+//NumGeometries returns the number of geometries
+func (c GeometryCollectionZ) NumGeometries() int {
+	return len(c)
+}
+
+//GeometryN returns the n-th (zero based) geometry, or nil if n is out of range
+func (c GeometryCollectionZ) GeometryN(n int) Geometry {
+	if n < 0 || n >= len(c) {
+		return nil
+	}
+	return c[n]
+}
+
+//GeometryType returns the name of the geometry type
+func (c GeometryCollectionM) GeometryType() string {
+	return "GeometryCollection"
+}
+
+//Dimension returns the topological dimension of the geometry collection: the highest dimension of its geometries,
+//or -1 if it has no geometry
+func (c GeometryCollectionM) Dimension() int {
+	d := -1
+	for _, g := range c {
+		if g.Dimension() > d {
+			d = g.Dimension()
+		}
+	}
+	return d
+}
+
+//CoordinateDimension returns the number of values of each coordinate
+func (c GeometryCollectionM) CoordinateDimension() int {
+	return 3
+}
+
+//IsEmpty returns true if the geometry collection has no non-empty geometry
+func (c GeometryCollectionM) IsEmpty() bool {
+	for _, g := range c {
+		if !g.IsEmpty() {
+			return false
+		}
+	}
+	return true
+}
+
+//NumPoints returns the number of points of the geometry collection
+func (c GeometryCollectionM) NumPoints() int {
+	n := 0
+	for _, g := range c {
+		n += g.NumPoints()
+	}
+	return n
+}
+
+//NumGeometries returns the number of geometries
+func (c GeometryCollectionM) NumGeometries() int {
+	return len(c)
+}
+
+//GeometryN returns the n-th (zero based) geometry, or nil if n is out of range
+func (c GeometryCollectionM) GeometryN(n int) Geometry {
+	if n < 0 || n >= len(c) {
+		return nil
+	}
+	return c[n]
+}
+
+//GeometryType returns the name of the geometry type
+func (c GeometryCollectionZM) GeometryType() string {
+	return "GeometryCollection"
+}
+
+//Dimension returns the topological dimension of the geometry collection: the highest dimension of its geometries,
+//or -1 if it has no geometry
+func (c GeometryCollectionZM) Dimension() int {
+	d := -1
+	for _, g := range c {
+		if g.Dimension() > d {
+			d = g.Dimension()
+		}
+	}
+	return d
+}
+
+//CoordinateDimension returns the number of values of each coordinate
+func (c GeometryCollectionZM) CoordinateDimension() int {
+	return 4
+}
+
+//IsEmpty returns true if the geometry collection has no non-empty geometry
+func (c GeometryCollectionZM) IsEmpty() bool {
+	for _, g := range c {
+		if !g.IsEmpty() {
+			return false
+		}
+	}
+	return true
+}
+
+//NumPoints returns the number of points of the geometry collection
+func (c GeometryCollectionZM) NumPoints() int {
+	n := 0
+	for _, g := range c {
+		n += g.NumPoints()
+	}
+	return n
+}
+
+//NumGeometries returns the number of geometries
+func (c GeometryCollectionZM) NumGeometries() int {
+	return len(c)
+}
+
+//GeometryN returns the n-th (zero based) geometry, or nil if n is out of range
+func (c GeometryCollectionZM) GeometryN(n int) Geometry {
+	if n < 0 || n >= len(c) {
+		return nil
+	}
+	return c[n]
+}
@@ -0,0 +1,29 @@
+// Copyright 2015 Simon HEGE. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package geom
+
+import "testing"
+
+func TestGeometryCollectionDimension(t *testing.T) {
+	tests := []struct {
+		g    Geometry
+		want int
+	}{
+		{GeometryCollection{}, -1},
+		{GeometryCollectionZ{}, -1},
+		{GeometryCollectionM{}, -1},
+		{GeometryCollectionZM{}, -1},
+		{GeometryCollection{GeometryCollection{}}, -1},
+		{GeometryCollection{&Point{}}, 0},
+		{GeometryCollection{LineString{}}, 1},
+		{GeometryCollection{&Point{}, Polygon{}, LineString{}}, 2},
+		{GeometryCollectionZ{&PointZ{}, LineStringZ{}}, 1},
+	}
+	for _, tt := range tests {
+		if got := tt.g.Dimension(); got != tt.want {
+			t.Errorf("%#v.Dimension() = %d, want %d", tt.g, got, tt.want)
+		}
+	}
+}
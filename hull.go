@@ -0,0 +1,344 @@
+// Copyright 2015 Simon HEGE. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package geom
+
+import (
+	"container/heap"
+	"math"
+	"sort"
+)
+
+//hullPoints returns the distinct points of g, sorted in lexicographic order
+func hullPoints(g Geometry) []Point {
+	var points []Point
+	if g == nil {
+		return points
+	}
+	g.Iterate(func(p []Point) error {
+		for _, pt := range p {
+			if !math.IsNaN(pt.X) && !math.IsNaN(pt.Y) {
+				points = append(points, pt)
+			}
+		}
+		return nil
+	})
+	sort.Slice(points, func(i, j int) bool {
+		return less(points[i], points[j])
+	})
+	return removeRepeated(points)
+}
+
+//ConvexHull returns the smallest convex geometry containing g: a Polygon (with a counter-clockwise shell),
+//a LineString if all the points are collinear, a Point if there is a single distinct point,
+//or an empty GeometryCollection if g is empty. Z and M values are ignored.
+func ConvexHull(g Geometry) Geometry {
+	return convexHull(hullPoints(g))
+}
+
+//convexHull computes the hull of sorted distinct points, with Andrew's monotone chain algorithm
+func convexHull(points []Point) Geometry {
+	switch len(points) {
+	case 0:
+		return GeometryCollection{}
+	case 1:
+		pt := points[0]
+		return &pt
+	}
+
+	hull := make(LineString, 0, 2*len(points))
+	//Lower chain, then upper chain
+	for _, pt := range points {
+		for len(hull) >= 2 && Orient2D(hull[len(hull)-2], hull[len(hull)-1], pt) <= 0 {
+			hull = hull[:len(hull)-1]
+		}
+		hull = append(hull, pt)
+	}
+	lower := len(hull)
+	for i := len(points) - 2; i >= 0; i-- {
+		pt := points[i]
+		for len(hull) > lower && Orient2D(hull[len(hull)-2], hull[len(hull)-1], pt) <= 0 {
+			hull = hull[:len(hull)-1]
+		}
+		hull = append(hull, pt)
+	}
+
+	if len(hull) < 4 {
+		//Collinear points: the hull goes to the last point and back
+		return LineString{points[0], points[len(points)-1]}
+	}
+	return Polygon{hull}
+}
+
+//delaunay returns the Delaunay triangulation of distinct points, as counter-clockwise triangles of point indices.
+//It uses the Bowyer-Watson algorithm, locating the points by walking through the triangulation.
+func delaunay(points []Point) [][3]int {
+	type triangle struct {
+		v       [3]int
+		n       [3]*triangle //n[i] is the neighbour across the edge opposite to v[i]
+		removed bool
+	}
+
+	//Super triangle, far enough to have a negligible effect on the hull
+	e := NewEnvelope()
+	for _, pt := range points {
+		e.ExtendPoint(pt)
+	}
+	size := math.Max(math.Max(e.Max.X-e.Min.X, e.Max.Y-e.Min.Y), 1) * 1e5
+	cx, cy := (e.Min.X+e.Max.X)/2, (e.Min.Y+e.Max.Y)/2
+	n := len(points)
+	all := make([]Point, n, n+3)
+	copy(all, points)
+	all = append(all, Point{X: cx - 2*size, Y: cy - size}, Point{X: cx + 2*size, Y: cy - size}, Point{X: cx, Y: cy + 2*size})
+
+	triangles := []*triangle{{v: [3]int{n, n + 1, n + 2}}}
+	last := triangles[0]
+	for i := 0; i < n; i++ {
+		p := all[i]
+
+		//Walk to the triangle containing p
+		t := last
+		for moved := true; moved; {
+			moved = false
+			for k := 0; k < 3; k++ {
+				a, b := all[t.v[(k+1)%3]], all[t.v[(k+2)%3]]
+				if t.n[k] != nil && Orient2D(a, b, p) < 0 {
+					t, moved = t.n[k], true
+					break
+				}
+			}
+		}
+
+		//Cavity made of the triangles whose circumcircle contains p
+		type cavityEdge struct {
+			a, b    int
+			outside *triangle
+		}
+		var boundary []cavityEdge
+		t.removed = true
+		stack := []*triangle{t}
+		for len(stack) > 0 {
+			t := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			for k := 0; k < 3; k++ {
+				nb := t.n[k]
+				if nb != nil && !nb.removed && InCircle(all[nb.v[0]], all[nb.v[1]], all[nb.v[2]], p) > 0 {
+					nb.removed = true
+					stack = append(stack, nb)
+					continue
+				}
+				if nb == nil || !nb.removed {
+					boundary = append(boundary, cavityEdge{a: t.v[(k+1)%3], b: t.v[(k+2)%3], outside: nb})
+				}
+			}
+		}
+
+		//New triangles joining p to the cavity boundary
+		byStart := make(map[int]*triangle, len(boundary))
+		byEnd := make(map[int]*triangle, len(boundary))
+		for _, ce := range boundary {
+			nt := &triangle{v: [3]int{ce.a, ce.b, i}}
+			nt.n[2] = ce.outside
+			if ce.outside != nil {
+				for k := 0; k < 3; k++ {
+					if ce.outside.v[(k+1)%3] == ce.b && ce.outside.v[(k+2)%3] == ce.a {
+						ce.outside.n[k] = nt
+					}
+				}
+			}
+			byStart[ce.a] = nt
+			byEnd[ce.b] = nt
+			triangles = append(triangles, nt)
+			last = nt
+		}
+		for _, ce := range boundary {
+			nt := byStart[ce.a]
+			nt.n[0] = byStart[ce.b]
+			nt.n[1] = byEnd[ce.a]
+		}
+	}
+
+	var result [][3]int
+	for _, t := range triangles {
+		if !t.removed && t.v[0] < n && t.v[1] < n && t.v[2] < n {
+			result = append(result, t.v)
+		}
+	}
+	return result
+}
+
+//hullTriangle is a triangle of the Delaunay triangulation used to compute concave hulls
+type hullTriangle struct {
+	v       [3]int
+	n       [3]int  //index of the neighbour across the edge opposite to v[i], -1 if none
+	size    float64 //removal criterion
+	removed bool
+}
+
+//hullQueue is a priority queue of triangles, the largest first
+type hullQueue []*hullTriangle
+
+func (q hullQueue) Len() int            { return len(q) }
+func (q hullQueue) Less(i, j int) bool  { return q[i].size > q[j].size }
+func (q hullQueue) Swap(i, j int)       { q[i], q[j] = q[j], q[i] }
+func (q *hullQueue) Push(x interface{}) { *q = append(*q, x.(*hullTriangle)) }
+func (q *hullQueue) Pop() interface{} {
+	old := *q
+	t := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return t
+}
+
+//ConcaveHull returns a polygon containing all the points of g, following its shape more closely than the convex hull.
+//
+//The Delaunay triangulation of the points is eroded from the outside, removing the triangles whose longest edge
+//is longer than min + lengthRatio * (max - min), where min and max are the shortest and longest edge lengths
+//of the triangulation. A ratio of 1 gives the convex hull, a ratio of 0 the most concave hull.
+//Triangles are only removed when the result stays a single polygon, and all points remain in it.
+//When allowHoles is true, holes not touching the shell can be created in the interior.
+//
+//If the points are collinear or fewer than 3, the result is the same as ConvexHull.
+func ConcaveHull(g Geometry, lengthRatio float64, allowHoles bool) Geometry {
+	points := hullPoints(g)
+	triangles := delaunay(points)
+	if len(triangles) == 0 {
+		return convexHull(points)
+	}
+
+	minLength, maxLength := math.Inf(1), 0.0
+	for _, t := range triangles {
+		for k := 0; k < 3; k++ {
+			l := hullEdgeLength(points, t[k], t[(k+1)%3])
+			minLength, maxLength = math.Min(minLength, l), math.Max(maxLength, l)
+		}
+	}
+	threshold := minLength + lengthRatio*(maxLength-minLength)
+	return erodeHull(points, triangles, threshold, allowHoles, func(t [3]int) float64 {
+		l := 0.0
+		for k := 0; k < 3; k++ {
+			l = math.Max(l, hullEdgeLength(points, t[k], t[(k+1)%3]))
+		}
+		return l
+	})
+}
+
+//AlphaShape returns a polygon containing all the points of g, computed like ConcaveHull,
+//but removing the triangles whose circumscribed circle has a radius larger than alpha.
+func AlphaShape(g Geometry, alpha float64, allowHoles bool) Geometry {
+	points := hullPoints(g)
+	triangles := delaunay(points)
+	if len(triangles) == 0 {
+		return convexHull(points)
+	}
+	return erodeHull(points, triangles, alpha, allowHoles, func(t [3]int) float64 {
+		a := hullEdgeLength(points, t[0], t[1])
+		b := hullEdgeLength(points, t[1], t[2])
+		c := hullEdgeLength(points, t[2], t[0])
+		return a * b * c / (2 * math.Abs(Orient2D(points[t[0]], points[t[1]], points[t[2]])))
+	})
+}
+
+func hullEdgeLength(points []Point, i, j int) float64 {
+	return math.Hypot(points[j].X-points[i].X, points[j].Y-points[i].Y)
+}
+
+//erodeHull removes the triangles larger than the threshold, the largest first, and returns the remaining area
+func erodeHull(points []Point, triangles [][3]int, threshold float64, allowHoles bool, size func([3]int) float64) Geometry {
+	tris := make([]*hullTriangle, len(triangles))
+	edges := make(map[[2]int]int, 3*len(triangles))
+	for i, t := range triangles {
+		tris[i] = &hullTriangle{v: t, size: size(t)}
+		for k := 0; k < 3; k++ {
+			edges[[2]int{t[(k+1)%3], t[(k+2)%3]}] = i
+		}
+	}
+
+	//Number of border edges at each vertex
+	borders := make([]int, len(points))
+	for _, t := range tris {
+		for k := 0; k < 3; k++ {
+			a, b := t.v[(k+1)%3], t.v[(k+2)%3]
+			var ok bool
+			if t.n[k], ok = edges[[2]int{b, a}]; !ok {
+				t.n[k] = -1
+				borders[a]++
+				borders[b]++
+			}
+		}
+	}
+	remaining := len(tris)
+
+	isBorder := func(t *hullTriangle, k int) bool {
+		return t.n[k] < 0 || tris[t.n[k]].removed
+	}
+	removable := func(t *hullTriangle) bool {
+		count := 0
+		for k := 0; k < 3; k++ {
+			if isBorder(t, k) {
+				count++
+			}
+		}
+		switch count {
+		case 0:
+			return allowHoles && borders[t.v[0]] == 0 && borders[t.v[1]] == 0 && borders[t.v[2]] == 0
+		case 1:
+			//The vertex opposite to the border edge must not touch the border, otherwise the polygon would be split
+			for k := 0; k < 3; k++ {
+				if isBorder(t, k) {
+					return borders[t.v[k]] == 0
+				}
+			}
+		}
+		//With more border edges, a point would be left out of the hull
+		return false
+	}
+
+	var queue hullQueue
+	for _, t := range tris {
+		if t.size > threshold {
+			queue = append(queue, t)
+		}
+	}
+	heap.Init(&queue)
+	for queue.Len() > 0 && remaining > 1 {
+		t := heap.Pop(&queue).(*hullTriangle)
+		if t.removed || !removable(t) {
+			continue
+		}
+		t.removed = true
+		remaining--
+		for k := 0; k < 3; k++ {
+			a, b := t.v[(k+1)%3], t.v[(k+2)%3]
+			if t.n[k] < 0 || tris[t.n[k]].removed {
+				borders[a]--
+				borders[b]--
+				continue
+			}
+			borders[a]++
+			borders[b]++
+			if nb := tris[t.n[k]]; nb.size > threshold {
+				heap.Push(&queue, nb)
+			}
+		}
+	}
+
+	//Border edges of the remaining triangles, with the area on their left
+	var directed []*directedEdge
+	outgoing := make(map[Point][]*directedEdge)
+	for _, t := range tris {
+		if t.removed {
+			continue
+		}
+		for k := 0; k < 3; k++ {
+			if isBorder(t, k) {
+				d := &directedEdge{from: points[t.v[(k+1)%3]], to: points[t.v[(k+2)%3]]}
+				directed = append(directed, d)
+				outgoing[d.from] = append(outgoing[d.from], d)
+			}
+		}
+	}
+	r := &overlayResult{}
+	r.buildPolygons(directed, outgoing)
+	return r.polygonal()
+}
@@ -0,0 +1,151 @@
+// Copyright 2015 Simon HEGE. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package geom
+
+import (
+	"math"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestConvexHull(t *testing.T) {
+	tests := []struct {
+		name string
+		g    Geometry
+		want Geometry
+	}{
+		{"empty", MultiPoint{}, GeometryCollection{}},
+		{"single point", MultiPoint{{X: 1, Y: 1}, {X: 1, Y: 1}}, &Point{X: 1, Y: 1}},
+		{"collinear", MultiPoint{{X: 1, Y: 1}, {X: 0, Y: 0}, {X: 2, Y: 2}}, LineString{{X: 0, Y: 0}, {X: 2, Y: 2}}},
+		{"square", MultiPoint{{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 2, Y: 0}, {X: 2, Y: 2}, {X: 0, Y: 2}, {X: 1, Y: 1}}, box(0, 0, 2, 2)},
+		{"concave polygon", Polygon{ring(0, 0, 4, 0, 2, 1, 4, 4, 0, 4)}, box(0, 0, 4, 4)},
+	}
+	for _, tt := range tests {
+		if got := ConvexHull(tt.g); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: ConvexHull() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestDelaunay(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	var mp MultiPoint
+	for i := 0; i < 300; i++ {
+		//Integer coordinates, to get repeated, collinear and cocircular points
+		mp = append(mp, Point{X: math.Floor(r.Float64() * 50), Y: math.Floor(r.Float64() * 50)})
+	}
+	points := hullPoints(mp)
+	triangles := delaunay(points)
+
+	area := 0.0
+	for _, tr := range triangles {
+		a, b, c := points[tr[0]], points[tr[1]], points[tr[2]]
+		if Orient2D(a, b, c) <= 0 {
+			t.Errorf("triangle %v %v %v is not counter-clockwise", a, b, c)
+		}
+		area += Orient2D(a, b, c) / 2
+		for _, pt := range points {
+			if InCircle(a, b, c, pt) > 0 {
+				t.Errorf("%v is inside the circumcircle of %v %v %v", pt, a, b, c)
+			}
+		}
+	}
+	if hull := Area(ConvexHull(mp)); area != hull {
+		t.Errorf("triangles area = %v, want the convex hull area %v", area, hull)
+	}
+}
+
+//grid returns the points of the integer grid from (0, 0) to (n, n) for which keep returns true
+func grid(n int, keep func(x, y float64) bool) MultiPoint {
+	var mp MultiPoint
+	for i := 0; i <= n; i++ {
+		for j := 0; j <= n; j++ {
+			if x, y := float64(i), float64(j); keep(x, y) {
+				mp = append(mp, Point{X: x, Y: y})
+			}
+		}
+	}
+	return mp
+}
+
+func TestConcaveHull(t *testing.T) {
+	//C shape: the notch on the right side is eroded
+	c := grid(10, func(x, y float64) bool { return x <= 3 || y <= 3 || y >= 7 })
+	convex := Area(ConvexHull(c))
+	previous := convex
+	for _, ratio := range []float64{1, 0.5, 0.2, 0} {
+		h := ConcaveHull(c, ratio, false)
+		p, ok := h.(Polygon)
+		if !ok || len(p) != 1 || !IsValid(p) {
+			t.Errorf("ratio %v: ConcaveHull() = %v, want a valid polygon without hole", ratio, h)
+			continue
+		}
+		for _, pt := range c {
+			if LocatePoint(pt, p) == Exterior {
+				t.Errorf("ratio %v: %v is outside the hull", ratio, pt)
+			}
+		}
+		a := Area(p)
+		if a > previous {
+			t.Errorf("ratio %v: area %v larger than the area %v of a larger ratio", ratio, a, previous)
+		}
+		previous = a
+		if ratio == 1 && a != convex {
+			t.Errorf("ratio 1: area %v, want the convex hull area %v", a, convex)
+		}
+		if ratio == 0 && LocatePoint(Point{X: 8, Y: 5}, p) != Exterior {
+			t.Errorf("ratio 0: the notch is not eroded: %v", p)
+		}
+	}
+
+	//Ring shape: the center is only eroded if holes are allowed
+	ringPoints := grid(10, func(x, y float64) bool { return math.Hypot(x-5, y-5) >= 3 })
+	center := Point{X: 5, Y: 5}
+	for _, allowHoles := range []bool{false, true} {
+		h := ConcaveHull(ringPoints, 0.2, allowHoles)
+		p, ok := h.(Polygon)
+		if !ok || !IsValid(p) {
+			t.Errorf("allowHoles %v: ConcaveHull() = %v, want a valid polygon", allowHoles, h)
+			continue
+		}
+		if holes := p.NumInteriorRings() > 0; holes != allowHoles {
+			t.Errorf("allowHoles %v: ConcaveHull() has %d holes", allowHoles, p.NumInteriorRings())
+		}
+		if inside := LocatePoint(center, p) == Interior; inside == allowHoles {
+			t.Errorf("allowHoles %v: location of the center %v", allowHoles, LocatePoint(center, p))
+		}
+	}
+
+	//Degenerate inputs give the convex hull
+	line := MultiPoint{{X: 0, Y: 0}, {X: 1, Y: 1}, {X: 2, Y: 2}}
+	if got := ConcaveHull(line, 0, false); !reflect.DeepEqual(got, ConvexHull(line)) {
+		t.Errorf("ConcaveHull() of collinear points = %v", got)
+	}
+}
+
+func TestAlphaShape(t *testing.T) {
+	ringPoints := grid(10, func(x, y float64) bool { return math.Hypot(x-5, y-5) >= 3 })
+
+	//Large alpha: no erosion
+	if got := AlphaShape(ringPoints, 100, true); Area(got) != Area(ConvexHull(ringPoints)) {
+		t.Errorf("AlphaShape() area = %v, want the convex hull area", Area(got))
+	}
+
+	//Alpha just above the circumradius of the grid triangles
+	h := AlphaShape(ringPoints, 0.8, true)
+	p, ok := h.(Polygon)
+	if !ok || !IsValid(p) || p.NumInteriorRings() != 1 {
+		t.Fatalf("AlphaShape() = %v, want a valid polygon with a hole", h)
+	}
+	if loc := LocatePoint(Point{X: 5, Y: 5}, p); loc != Exterior {
+		t.Errorf("AlphaShape() location of the center = %v", loc)
+	}
+	for _, pt := range ringPoints {
+		if LocatePoint(pt, p) == Exterior {
+			t.Errorf("%v is outside the alpha shape", pt)
+		}
+	}
+}
@@ -0,0 +1,349 @@
+// Copyright 2015 Simon HEGE. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package geom
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+)
+
+//projectLike converts a geometry returned by ForceZM to the dimension variant of ref
+func projectLike(g GeometryZM, ref Geometry) Geometry {
+	_, hasZ := ref.(GeometryZ)
+	_, hasM := ref.(GeometryM)
+	switch {
+	case hasZ && hasM:
+		return g
+	case hasZ:
+		return projectZ(g)
+	case hasM:
+		return projectM(g)
+	}
+	return project2D(g)
+}
+
+//linesZM returns the lines of a LineString or MultiLineString (any dimension variant) as ZM lines.
+//If measured is true, the geometry must have M values.
+func linesZM(g Geometry, measured bool) ([]LineStringZM, error) {
+	if g == nil {
+		return nil, fmt.Errorf("Unsupported geometry type: %v", reflect.TypeOf(g))
+	}
+	if _, ok := g.(GeometryM); measured && !ok {
+		return nil, fmt.Errorf("Unsupported geometry type: %v", reflect.TypeOf(g))
+	}
+	switch g.GeometryType() {
+	case "LineString", "MultiLineString":
+	default:
+		return nil, fmt.Errorf("Unsupported geometry type: %v", reflect.TypeOf(g))
+	}
+
+	zm, err := ForceZM(g, 0, 0)
+	if err != nil {
+		return nil, err
+	}
+	switch zm := zm.(type) {
+	case LineStringZM:
+		return []LineStringZM{zm}, nil
+	case MultiLineStringZM:
+		return []LineStringZM(zm), nil
+	}
+	return nil, fmt.Errorf("Unsupported geometry type: %v", reflect.TypeOf(g))
+}
+
+//interpolateZM returns the point at t along the segment [a, b], with interpolated Z and M values
+func interpolateZM(a, b PointZM, t float64) PointZM {
+	switch t {
+	case 0:
+		return a
+	case 1:
+		return b
+	}
+	return PointZM{
+		PointZ: PointZ{
+			Point: Point{X: a.X + t*(b.X-a.X), Y: a.Y + t*(b.Y-a.Y)},
+			Z:     a.Z + t*(b.Z-a.Z),
+		},
+		M: a.M + t*(b.M-a.M),
+	}
+}
+
+//offsetLine returns the line moved by distance to its left (to its right if negative), keeping Z and M values.
+//Interior vertices are moved along the bisector of their segments, so that the segments stay parallel to the original ones.
+func offsetLine(l LineStringZM, distance float64) LineStringZM {
+	if distance == 0 || len(l) < 2 {
+		return l
+	}
+
+	//Normals of the segments, taken from a neighbour for zero length segments
+	normals := make([]Point, len(l)-1)
+	valid := -1
+	for i := range normals {
+		if l[i].Point != l[i+1].Point {
+			normals[i] = leftNormal(direction(l[i].Point, l[i+1].Point))
+			if valid < 0 {
+				for j := 0; j < i; j++ {
+					normals[j] = normals[i]
+				}
+			}
+			valid = i
+		} else if valid >= 0 {
+			normals[i] = normals[valid]
+		}
+	}
+	if valid < 0 {
+		return l
+	}
+
+	r := make(LineStringZM, len(l))
+	for i, pt := range l {
+		var n Point
+		switch i {
+		case 0:
+			n = normals[0]
+		case len(l) - 1:
+			n = normals[i-1]
+		default:
+			n1, n2 := normals[i-1], normals[i]
+			n = n1
+			if cos := n1.X*n2.X + n1.Y*n2.Y; cos > -1+1e-9 {
+				n = Point{X: (n1.X + n2.X) / (1 + cos), Y: (n1.Y + n2.Y) / (1 + cos)}
+			}
+		}
+		r[i] = pt
+		r[i].Point = offset(pt.Point, n, distance)
+	}
+	return r
+}
+
+//AddMeasure returns a copy of the LineString or MultiLineString g with M values increasing linearly
+//with the length, from start at the first point to end at the last one. For a MultiLineString,
+//the measures run over the total length of its lines. Existing M values are replaced, and Z values are kept:
+//the result is a LineStringM, LineStringZM, MultiLineStringM or MultiLineStringZM.
+//If g has a length of 0, all the measures are set to start.
+func AddMeasure(g Geometry, start, end float64) (GeometryM, error) {
+	lines, err := linesZM(g, false)
+	if err != nil {
+		return nil, err
+	}
+
+	total := 0.0
+	for _, l := range lines {
+		for i := 1; i < len(l); i++ {
+			total += math.Hypot(l[i].X-l[i-1].X, l[i].Y-l[i-1].Y)
+		}
+	}
+	length := 0.0
+	for _, l := range lines {
+		for i := range l {
+			if i > 0 {
+				length += math.Hypot(l[i].X-l[i-1].X, l[i].Y-l[i-1].Y)
+			}
+			l[i].M = start
+			if total > 0 {
+				l[i].M = start + (end-start)*length/total
+			}
+		}
+	}
+
+	var zm GeometryZM = MultiLineStringZM(lines)
+	if g.GeometryType() == "LineString" {
+		zm = lines[0]
+	}
+	if _, ok := g.(GeometryZ); ok {
+		return zm, nil
+	}
+	return projectM(zm), nil
+}
+
+//LocateAlong returns the points of the measured line g (LineStringM or MultiLineStringM, with or without Z)
+//whose M value is measure, as a MultiPointM (or MultiPointZM), empty if none.
+//Z values are interpolated. With a non-zero offset, the points are moved perpendicularly to the line,
+//to its left if positive and to its right if negative.
+func LocateAlong(g Geometry, measure, offsetDistance float64) (Geometry, error) {
+	lines, err := linesZM(g, true)
+	if err != nil {
+		return nil, err
+	}
+
+	points := MultiPointZM{}
+	for _, l := range lines {
+		if len(l) == 1 && l[0].M == measure {
+			points = append(points, l[0])
+		}
+		for i := 1; i < len(l); i++ {
+			a, b := l[i-1], l[i]
+			var t float64
+			switch {
+			case a.M == b.M:
+				if a.M != measure {
+					continue
+				}
+			case (a.M <= measure && measure <= b.M) || (b.M <= measure && measure <= a.M):
+				t = (measure - a.M) / (b.M - a.M)
+			default:
+				continue
+			}
+			//Points at a vertex are found at the start of the following segment
+			if t == 1 && i < len(l)-1 {
+				continue
+			}
+			pt := interpolateZM(a, b, t)
+			if a.Point != b.Point {
+				pt.Point = offset(pt.Point, leftNormal(direction(a.Point, b.Point)), offsetDistance)
+			}
+			points = append(points, pt)
+		}
+	}
+	return projectLike(points, g), nil
+}
+
+//LocateBetween returns the parts of the measured line g (LineStringM or MultiLineStringM, with or without Z)
+//whose M values are between from and to (in any order), as a MultiLineStringM (or MultiLineStringZM),
+//empty if none. Z and M values are interpolated at the cut points. Parts reduced to a single point are ignored:
+//use LocateAlong to find them. With a non-zero offset, the parts are moved perpendicularly to the line,
+//to its left if positive and to its right if negative.
+func LocateBetween(g Geometry, from, to, offsetDistance float64) (Geometry, error) {
+	lines, err := linesZM(g, true)
+	if err != nil {
+		return nil, err
+	}
+	if from > to {
+		from, to = to, from
+	}
+
+	parts := MultiLineStringZM{}
+	var current LineStringZM
+	flush := func() {
+		if len(current) > 1 {
+			parts = append(parts, offsetLine(current, offsetDistance))
+		}
+		current = nil
+	}
+	for _, l := range lines {
+		for i := 1; i < len(l); i++ {
+			a, b := l[i-1], l[i]
+
+			//Part of the segment in the range
+			t0, t1 := 0.0, 1.0
+			if a.M == b.M {
+				if a.M < from || a.M > to {
+					flush()
+					continue
+				}
+			} else {
+				t0, t1 = (from-a.M)/(b.M-a.M), (to-a.M)/(b.M-a.M)
+				if t0 > t1 {
+					t0, t1 = t1, t0
+				}
+				t0, t1 = math.Max(t0, 0), math.Min(t1, 1)
+				if t0 > t1 {
+					flush()
+					continue
+				}
+			}
+
+			if t0 > 0 {
+				flush()
+			}
+			start, end := interpolateZM(a, b, t0), interpolateZM(a, b, t1)
+			if len(current) == 0 || current[len(current)-1] != start {
+				flush()
+				current = append(current, start)
+			}
+			if end != start {
+				current = append(current, end)
+			}
+			if t1 < 1 {
+				flush()
+			}
+		}
+		flush()
+	}
+	return projectLike(parts, g), nil
+}
+
+//InterpolatePoint returns the M value of the measured line g (LineStringM or MultiLineStringM, with or without Z)
+//at the location closest to pt, interpolated along its segment. NaN is returned if g is empty.
+func InterpolatePoint(g Geometry, pt Point) (float64, error) {
+	lines, err := linesZM(g, true)
+	if err != nil {
+		return 0, err
+	}
+
+	measure, best := math.NaN(), math.Inf(1)
+	for _, l := range lines {
+		if len(l) == 1 {
+			if d := math.Hypot(pt.X-l[0].X, pt.Y-l[0].Y); d < best {
+				measure, best = l[0].M, d
+			}
+		}
+		for i := 1; i < len(l); i++ {
+			a, b := l[i-1], l[i]
+			proj, t := projectOnSegment(pt, a.Point, b.Point)
+			if d := math.Hypot(pt.X-proj.X, pt.Y-proj.Y); d < best {
+				measure, best = a.M+t*(b.M-a.M), d
+			}
+		}
+	}
+	return measure, nil
+}
+
+//LineSubstring returns the part of the LineString l (any dimension variant) between the fractions start and end
+//of its length, with 0 <= start <= end <= 1. Z and M values are interpolated at the cut points.
+//The result has the same type as l, or is a point if start and end are equal.
+func LineSubstring(l Geometry, start, end float64) (Geometry, error) {
+	if start < 0 || end > 1 || start > end {
+		return nil, fmt.Errorf("Invalid fractions: %v %v", start, end)
+	}
+	if l == nil || l.GeometryType() != "LineString" {
+		return nil, fmt.Errorf("Unsupported geometry type: %v", reflect.TypeOf(l))
+	}
+	lines, err := linesZM(l, false)
+	if err != nil {
+		return nil, err
+	}
+	line := lines[0]
+	if len(line) == 0 {
+		return cloneGeometry(l), nil
+	}
+
+	lengths := make([]float64, len(line))
+	for i := 1; i < len(line); i++ {
+		lengths[i] = lengths[i-1] + math.Hypot(line[i].X-line[i-1].X, line[i].Y-line[i-1].Y)
+	}
+	total := lengths[len(line)-1]
+
+	//locate returns the point at a distance along the line, and the index of the following vertex
+	locate := func(distance float64) (PointZM, int) {
+		for i := 1; i < len(line); i++ {
+			if distance <= lengths[i] {
+				t := 0.0
+				if lengths[i] > lengths[i-1] {
+					t = (distance - lengths[i-1]) / (lengths[i] - lengths[i-1])
+				}
+				return interpolateZM(line[i-1], line[i], t), i
+			}
+		}
+		return line[len(line)-1], len(line)
+	}
+
+	first, i := locate(start * total)
+	last, j := locate(end * total)
+	if start == end {
+		pt := first
+		return projectLike(&pt, l), nil
+	}
+	sub := LineStringZM{first}
+	for ; i < j; i++ {
+		if line[i] != sub[len(sub)-1] {
+			sub = append(sub, line[i])
+		}
+	}
+	if last != sub[len(sub)-1] || len(sub) == 1 {
+		sub = append(sub, last)
+	}
+	return projectLike(sub, l), nil
+}
@@ -99,22 +99,54 @@ func (l LineStringZM) EnvelopeZM() *EnvelopeZM {
 
 //Clone returns a deep copy of the line
 func (l LineString) Clone() Geometry {
-	return &l
+	clone := l.clone()
+	return &clone
+}
+
+//clone returns a deep copy of the line, by value
+func (l LineString) clone() LineString {
+	c := make(LineString, len(l))
+	copy(c, l)
+	return c
 }
 
 //Clone returns a deep copy of the line
 func (l LineStringZ) Clone() Geometry {
-	return &l
+	clone := l.clone()
+	return &clone
+}
+
+//clone returns a deep copy of the line, by value
+func (l LineStringZ) clone() LineStringZ {
+	c := make(LineStringZ, len(l))
+	copy(c, l)
+	return c
 }
 
 //Clone returns a deep copy of the line
 func (l LineStringM) Clone() Geometry {
-	return &l
+	clone := l.clone()
+	return &clone
+}
+
+//clone returns a deep copy of the line, by value
+func (l LineStringM) clone() LineStringM {
+	c := make(LineStringM, len(l))
+	copy(c, l)
+	return c
 }
 
 //Clone returns a deep copy of the line
 func (l LineStringZM) Clone() Geometry {
-	return &l
+	clone := l.clone()
+	return &clone
+}
+
+//clone returns a deep copy of the line, by value
+func (l LineStringZM) clone() LineStringZM {
+	c := make(LineStringZM, len(l))
+	copy(c, l)
+	return c
 }
 
 //Iterate walks over the points (and can modify in situ) the line
@@ -160,3 +192,333 @@ func (l LineStringZM) Iterate(f func([]Point) error) error {
 	}
 	return err
 }
+
+//IterateZ walks over the points (and can modify in situ) the line
+func (l LineStringZ) IterateZ(f func([]PointZ) error) error {
+	return f(l)
+}
+
+//IterateM walks over the points (and can modify in situ) the line
+func (l LineStringM) IterateM(f func([]PointM) error) error {
+	return f(l)
+}
+
+//IterateZ walks over the points (and can modify in situ) the line
+func (l LineStringZM) IterateZ(f func([]PointZ) error) error {
+	points := make([]PointZ, len(l))
+	for i := range l {
+		points[i] = l[i].PointZ
+	}
+	err := f(points)
+	for i := range l {
+		l[i].PointZ = points[i]
+	}
+	return err
+}
+
+//IterateM walks over the points (and can modify in situ) the line
+func (l LineStringZM) IterateM(f func([]PointM) error) error {
+	points := make([]PointM, len(l))
+	for i := range l {
+		points[i] = PointM{Point: l[i].Point, M: l[i].M}
+	}
+	err := f(points)
+	for i := range l {
+		l[i].Point = points[i].Point
+		l[i].M = points[i].M
+	}
+	return err
+}
+
+//IterateZM walks over the points (and can modify in situ) the line
+func (l LineStringZM) IterateZM(f func([]PointZM) error) error {
+	return f(l)
+}
+
+//GeometryType returns the name of the geometry type
+func (l LineString) GeometryType() string {
+	return "LineString"
+}
+
+//Dimension returns the topological dimension of the line: 1
+func (l LineString) Dimension() int {
+	return 1
+}
+
+//CoordinateDimension returns the number of values of each coordinate
+func (l LineString) CoordinateDimension() int {
+	return 2
+}
+
+//IsEmpty returns true if the line has no points
+func (l LineString) IsEmpty() bool {
+	return len(l) == 0
+}
+
+//NumPoints returns the number of points of the line
+func (l LineString) NumPoints() int {
+	return len(l)
+}
+
+//NumGeometries returns the number of geometries: 1
+func (l LineString) NumGeometries() int {
+	return 1
+}
+
+//GeometryN returns the line itself if n is 0, nil otherwise
+func (l LineString) GeometryN(n int) Geometry {
+	if n != 0 {
+		return nil
+	}
+	return l
+}
+
+//StartPoint returns a copy of the first point of the line, or nil if the line is empty
+func (l LineString) StartPoint() Geometry {
+	if len(l) == 0 {
+		return nil
+	}
+	pt := l[0]
+	return &pt
+}
+
+//EndPoint returns a copy of the last point of the line, or nil if the line is empty
+func (l LineString) EndPoint() Geometry {
+	if len(l) == 0 {
+		return nil
+	}
+	pt := l[len(l)-1]
+	return &pt
+}
+
+//IsClosed returns true if the first and last points of the line are equal
+func (l LineString) IsClosed() bool {
+	return len(l) > 0 && l[0] == l[len(l)-1]
+}
+
+//IsRing returns true if the line is closed and simple
+func (l LineString) IsRing() bool {
+	return len(l) >= 4 && l.IsClosed() && isSimple(l)
+}
+
+//GeometryType returns the name of the geometry type
+func (l LineStringZ) GeometryType() string {
+	return "LineString"
+}
+
+//Dimension returns the topological dimension of the line: 1
+func (l LineStringZ) Dimension() int {
+	return 1
+}
+
+//CoordinateDimension returns the number of values of each coordinate
+func (l LineStringZ) CoordinateDimension() int {
+	return 3
+}
+
+//IsEmpty returns true if the line has no points
+func (l LineStringZ) IsEmpty() bool {
+	return len(l) == 0
+}
+
+//NumPoints returns the number of points of the line
+func (l LineStringZ) NumPoints() int {
+	return len(l)
+}
+
+//NumGeometries returns the number of geometries: 1
+func (l LineStringZ) NumGeometries() int {
+	return 1
+}
+
+//GeometryN returns the line itself if n is 0, nil otherwise
+func (l LineStringZ) GeometryN(n int) Geometry {
+	if n != 0 {
+		return nil
+	}
+	return l
+}
+
+//StartPoint returns a copy of the first point of the line, or nil if the line is empty
+func (l LineStringZ) StartPoint() Geometry {
+	if len(l) == 0 {
+		return nil
+	}
+	pt := l[0]
+	return &pt
+}
+
+//EndPoint returns a copy of the last point of the line, or nil if the line is empty
+func (l LineStringZ) EndPoint() Geometry {
+	if len(l) == 0 {
+		return nil
+	}
+	pt := l[len(l)-1]
+	return &pt
+}
+
+//IsClosed returns true if the first and last points of the line are equal
+func (l LineStringZ) IsClosed() bool {
+	return len(l) > 0 && l[0] == l[len(l)-1]
+}
+
+//IsRing returns true if the line is closed and simple
+func (l LineStringZ) IsRing() bool {
+	if len(l) < 4 || !l.IsClosed() {
+		return false
+	}
+	simple := false
+	l.Iterate(func(points []Point) error {
+		simple = isSimple(points)
+		return nil
+	})
+	return simple
+}
+
+//GeometryType returns the name of the geometry type
+func (l LineStringM) GeometryType() string {
+	return "LineString"
+}
+
+//Dimension returns the topological dimension of the line: 1
+func (l LineStringM) Dimension() int {
+	return 1
+}
+
+//CoordinateDimension returns the number of values of each coordinate
+func (l LineStringM) CoordinateDimension() int {
+	return 3
+}
+
+//IsEmpty returns true if the line has no points
+func (l LineStringM) IsEmpty() bool {
+	return len(l) == 0
+}
+
+//NumPoints returns the number of points of the line
+func (l LineStringM) NumPoints() int {
+	return len(l)
+}
+
+//NumGeometries returns the number of geometries: 1
+func (l LineStringM) NumGeometries() int {
+	return 1
+}
+
+//GeometryN returns the line itself if n is 0, nil otherwise
+func (l LineStringM) GeometryN(n int) Geometry {
+	if n != 0 {
+		return nil
+	}
+	return l
+}
+
+//StartPoint returns a copy of the first point of the line, or nil if the line is empty
+func (l LineStringM) StartPoint() Geometry {
+	if len(l) == 0 {
+		return nil
+	}
+	pt := l[0]
+	return &pt
+}
+
+//EndPoint returns a copy of the last point of the line, or nil if the line is empty
+func (l LineStringM) EndPoint() Geometry {
+	if len(l) == 0 {
+		return nil
+	}
+	pt := l[len(l)-1]
+	return &pt
+}
+
+//IsClosed returns true if the first and last points of the line are equal (M values are ignored)
+func (l LineStringM) IsClosed() bool {
+	return len(l) > 0 && l[0].Point == l[len(l)-1].Point
+}
+
+//IsRing returns true if the line is closed and simple
+func (l LineStringM) IsRing() bool {
+	if len(l) < 4 || !l.IsClosed() {
+		return false
+	}
+	simple := false
+	l.Iterate(func(points []Point) error {
+		simple = isSimple(points)
+		return nil
+	})
+	return simple
+}
+
+//GeometryType returns the name of the geometry type
+func (l LineStringZM) GeometryType() string {
+	return "LineString"
+}
+
+//Dimension returns the topological dimension of the line: 1
+func (l LineStringZM) Dimension() int {
+	return 1
+}
+
+//CoordinateDimension returns the number of values of each coordinate
+func (l LineStringZM) CoordinateDimension() int {
+	return 4
+}
+
+//IsEmpty returns true if the line has no points
+func (l LineStringZM) IsEmpty() bool {
+	return len(l) == 0
+}
+
+//NumPoints returns the number of points of the line
+func (l LineStringZM) NumPoints() int {
+	return len(l)
+}
+
+//NumGeometries returns the number of geometries: 1
+func (l LineStringZM) NumGeometries() int {
+	return 1
+}
+
+//GeometryN returns the line itself if n is 0, nil otherwise
+func (l LineStringZM) GeometryN(n int) Geometry {
+	if n != 0 {
+		return nil
+	}
+	return l
+}
+
+//StartPoint returns a copy of the first point of the line, or nil if the line is empty
+func (l LineStringZM) StartPoint() Geometry {
+	if len(l) == 0 {
+		return nil
+	}
+	pt := l[0]
+	return &pt
+}
+
+//EndPoint returns a copy of the last point of the line, or nil if the line is empty
+func (l LineStringZM) EndPoint() Geometry {
+	if len(l) == 0 {
+		return nil
+	}
+	pt := l[len(l)-1]
+	return &pt
+}
+
+//IsClosed returns true if the first and last points of the line are equal (M values are ignored)
+func (l LineStringZM) IsClosed() bool {
+	return len(l) > 0 && l[0].PointZ == l[len(l)-1].PointZ
+}
+
+//IsRing returns true if the line is closed and simple
+func (l LineStringZM) IsRing() bool {
+	if len(l) < 4 || !l.IsClosed() {
+		return false
+	}
+	simple := false
+	l.Iterate(func(points []Point) error {
+		simple = isSimple(points)
+		return nil
+	})
+	return simple
+}
@@ -0,0 +1,39 @@
+// Copyright 2015 Simon HEGE. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package geom
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestStartEndPointCopies(t *testing.T) {
+	pt := Point{X: 1, Y: 2}
+	ptZ := PointZ{Point: pt, Z: 3}
+	ptM := PointM{Point: pt, M: 4}
+	ptZM := PointZM{PointZ: ptZ, M: 4}
+	lines := []Curve{
+		LineString{pt, pt},
+		LineStringZ{ptZ, ptZ},
+		LineStringM{ptM, ptM},
+		LineStringZM{ptZM, ptZM},
+	}
+	for _, l := range lines {
+		before := l.Clone()
+		for _, end := range []Geometry{l.StartPoint(), l.EndPoint()} {
+			end.Iterate(func(points []Point) error {
+				points[0].X = 10
+				return nil
+			})
+		}
+		if !reflect.DeepEqual(before, l.Clone()) {
+			t.Errorf("%T: modifying the start or end point changes the line", l)
+		}
+	}
+
+	if LineString(nil).StartPoint() != nil || LineStringZM(nil).EndPoint() != nil {
+		t.Error("StartPoint and EndPoint of an empty line should be nil")
+	}
+}
@@ -0,0 +1,98 @@
+// Copyright 2015 Simon HEGE. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package geom
+
+//Location is the topological location of a point relative to a geometry
+type Location int
+
+//Locations of a point relative to a geometry
+const (
+	Interior Location = iota
+	Boundary
+	Exterior
+)
+
+func (l Location) String() string {
+	switch l {
+	case Interior:
+		return "Interior"
+	case Boundary:
+		return "Boundary"
+	case Exterior:
+		return "Exterior"
+	}
+	return "Unknown"
+}
+
+//locateInRing returns the location of pt relative to the area enclosed by the ring.
+//The ring is expected to be closed. The crossing number is computed with exact orientation tests,
+//so that points on the ring are always reported on the boundary.
+func locateInRing(pt Point, ring []Point) Location {
+	crossings := 0
+	for i := 1; i < len(ring); i++ {
+		p1, p2 := ring[i-1], ring[i]
+
+		//Segment strictly on the left of the point
+		if p1.X < pt.X && p2.X < pt.X {
+			continue
+		}
+		if pt == p2 {
+			return Boundary
+		}
+		//Horizontal segment on the ray
+		if p1.Y == pt.Y && p2.Y == pt.Y {
+			if (p1.X <= pt.X && pt.X <= p2.X) || (p2.X <= pt.X && pt.X <= p1.X) {
+				return Boundary
+			}
+			continue
+		}
+		//Segment crossing the ray (the lower end point is included, the upper one is excluded)
+		if (p1.Y > pt.Y && p2.Y <= pt.Y) || (p2.Y > pt.Y && p1.Y <= pt.Y) {
+			orient := Orient2D(p1, p2, pt)
+			if orient == 0 {
+				return Boundary
+			}
+			if p2.Y < p1.Y {
+				orient = -orient
+			}
+			if orient > 0 {
+				crossings++
+			}
+		}
+	}
+	if crossings%2 == 1 {
+		return Interior
+	}
+	return Exterior
+}
+
+//locateInSurface returns the location of pt relative to a polygon with holes
+func locateInSurface(pt Point, s Surface) Location {
+	if s.IsEmpty() {
+		return Exterior
+	}
+	var rings [][]Point
+	s.Iterate(func(ring []Point) error {
+		rings = append(rings, ring)
+		return nil
+	})
+	return locateInRings(pt, rings)
+}
+
+//LocatePoint returns the location of pt relative to the polygonal geometry g (Polygon or MultiPolygon,
+//any dimension variant). Geometry collections are handled recursively, other geometries are ignored.
+//The polygons are expected to be valid: holes inside their shell and polygons not overlapping.
+func LocatePoint(pt Point, g Geometry) Location {
+	loc := Exterior
+	surfaces(g, func(s Surface) {
+		if loc == Interior {
+			return
+		}
+		if l := locateInSurface(pt, s); l < loc {
+			loc = l
+		}
+	})
+	return loc
+}
@@ -0,0 +1,244 @@
+// Copyright 2015 Simon HEGE. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package geom
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+)
+
+//MakeValid returns a valid geometry covering the same points as g.
+//
+//Polygons are repaired with the linework method: all the rings of a Polygon or MultiPolygon are noded together,
+//and the result area is made of the faces enclosed by an odd number of rings (even-odd rule). Bow-ties are thus split
+//into several polygons, ring orientations are ignored, holes outside their shell become polygons, and the parts
+//where rings overlap are removed. Parts of the rings which collapse to lines or points, and do not bound the area,
+//are returned with the polygons in a GeometryCollection.
+//
+//Repeated and non-finite points are removed from all the geometries, lines with a single distinct point
+//become points, and geometry collections are repaired recursively. Valid geometries are rebuilt too:
+//polygon shells become counter-clockwise and holes clockwise, as in Overlay results.
+//Z values are kept as by Overlay, M values are dropped from repaired geometries.
+func MakeValid(g Geometry) (Geometry, error) {
+	if g == nil {
+		return nil, fmt.Errorf("Unsupported geometry type: %v", reflect.TypeOf(g))
+	}
+	switch g.GeometryType() {
+	case "GeometryCollection":
+		parts := make([]Geometry, g.NumGeometries())
+		for i := range parts {
+			var err error
+			if parts[i], err = MakeValid(g.GeometryN(i)); err != nil {
+				return nil, err
+			}
+		}
+		if _, ok := g.(GeometryZ); ok {
+			c := make(GeometryCollectionZ, len(parts))
+			for i := range parts {
+				c[i] = parts[i].(GeometryZ)
+			}
+			return c, nil
+		}
+		return GeometryCollection(parts), nil
+	case "Point", "MultiPoint", "LineString", "MultiLineString", "Polygon", "MultiPolygon":
+	default:
+		return nil, fmt.Errorf("Unsupported geometry type: %v", reflect.TypeOf(g))
+	}
+
+	//Cleaned parts, with vertices snapped as for overlays
+	var points []Point
+	var lines [][]Point
+	e := NewEnvelope()
+	g.Iterate(func(p []Point) error {
+		var line []Point
+		for _, pt := range p {
+			if !math.IsNaN(pt.X) && !math.IsNaN(pt.Y) && !math.IsInf(pt.X, 0) && !math.IsInf(pt.Y, 0) {
+				line = append(line, pt)
+				e.ExtendPoint(pt)
+			}
+		}
+		if g.Dimension() == 0 {
+			points = append(points, line...)
+		} else if len(line) > 0 {
+			lines = append(lines, line)
+		}
+		return nil
+	})
+	snapper := newNodeSnapper(e)
+	for _, line := range lines {
+		for i, pt := range line {
+			line[i] = snapper.snap(pt)
+		}
+	}
+
+	var segments []*segment
+	r := &overlayResult{}
+	dim := g.Dimension()
+	switch dim {
+	case 0:
+		r.points = points
+	case 1:
+		for _, line := range lines {
+			line = removeRepeated(line)
+			if len(line) == 1 {
+				r.points = append(r.points, line[0])
+				continue
+			}
+			r.lines = append(r.lines, line)
+		}
+	case 2:
+		segments = r.buildValidArea(lines, snapper)
+	}
+	r.z = overlayZ(&topologyGraph{segments: segments}, g, g)
+
+	if dim == 0 && len(r.points) == 0 {
+		if r.z != nil {
+			return GeometryCollectionZ{}, nil
+		}
+		return GeometryCollection{}, nil
+	}
+	return r.geometry(dim), nil
+}
+
+//buildValidArea computes the polygons enclosed by an odd number of the rings, and the collapsed linework.
+//It returns the noded segments of the rings.
+func (r *overlayResult) buildValidArea(rings [][]Point, snapper *nodeSnapper) []*segment {
+	var segments []*segment
+	var collapsed []Point
+	for _, ring := range rings {
+		ring = removeRepeated(ring)
+		if ring[0] != ring[len(ring)-1] {
+			ring = append(ring, ring[0])
+		}
+		if len(ring) == 1 {
+			collapsed = append(collapsed, ring[0])
+			continue
+		}
+		for j := 1; j < len(ring); j++ {
+			segments = append(segments, &segment{a: ring[j-1], b: ring[j], ring: true})
+		}
+	}
+	nodeSegments(segments, snapper)
+
+	//Noded edges, with the parity of the number of rings passing through them
+	var edges []*edge
+	index := make(map[[2]Point]*edge)
+	odd := make(map[*edge]bool)
+	for _, s := range segments {
+		for i := 1; i < len(s.nodes); i++ {
+			p, q := s.nodes[i-1], s.nodes[i]
+			if p == q {
+				continue
+			}
+			if less(q, p) {
+				p, q = q, p
+			}
+			e, ok := index[[2]Point{p, q}]
+			if !ok {
+				e = &edge{a: p, b: q}
+				index[[2]Point{p, q}] = e
+				edges = append(edges, e)
+			}
+			odd[e] = !odd[e]
+		}
+	}
+	var boundary []*edge
+	for _, e := range edges {
+		if odd[e] {
+			boundary = append(boundary, e)
+		}
+	}
+
+	//Faces of the boundary edges, whose parity changes across each edge
+	var directed []*directedEdge
+	outgoing := make(map[Point][]*directedEdge)
+	source := make(map[*directedEdge]*edge)
+	for _, e := range boundary {
+		for _, d := range []*directedEdge{{from: e.a, to: e.b}, {from: e.b, to: e.a}} {
+			directed = append(directed, d)
+			outgoing[d.from] = append(outgoing[d.from], d)
+			source[d] = e
+		}
+	}
+	next := faceWalker(outgoing)
+
+	var area []*directedEdge
+	areaOutgoing := make(map[Point][]*directedEdge)
+	for _, start := range directed {
+		if start.visited {
+			continue
+		}
+		var face []*directedEdge
+		for d := start; d != nil && !d.visited; d = next(d) {
+			d.visited = true
+			face = append(face, d)
+		}
+
+		//Parity on the left of a non horizontal edge of the face
+		inside := false
+		for _, d := range face {
+			if d.from.Y == d.to.Y {
+				continue
+			}
+			mid := source[d].mid()
+			inside = rayParity(mid, boundary, source[d])
+			if d.to.Y > d.from.Y {
+				//The ray goes to the right side of the edge
+				inside = !inside
+			}
+			break
+		}
+		if inside {
+			for _, d := range face {
+				a := &directedEdge{from: d.from, to: d.to}
+				area = append(area, a)
+				areaOutgoing[a.from] = append(areaOutgoing[a.from], a)
+			}
+		}
+	}
+	r.buildPolygons(area, areaOutgoing)
+
+	//Collapsed linework and points, outside of the area
+	covered := make(map[Point]bool)
+	for _, e := range boundary {
+		covered[e.a], covered[e.b] = true, true
+	}
+	var lineEdges []*edge
+	for _, e := range edges {
+		if !odd[e] && !rayParity(e.mid(), boundary, nil) {
+			e.labels[0] = edgeLabel{line: true, forward: true}
+			lineEdges = append(lineEdges, e)
+			covered[e.a], covered[e.b] = true, true
+		}
+	}
+	r.buildLines(lineEdges)
+	for _, pt := range collapsed {
+		if !covered[pt] && !rayParity(pt, boundary, nil) {
+			covered[pt] = true
+			r.points = append(r.points, pt)
+		}
+	}
+	return segments
+}
+
+//rayParity returns true if the ray from pt towards the positive X axis crosses an odd number of edges.
+//The skip edge is ignored.
+func rayParity(pt Point, edges []*edge, skip *edge) bool {
+	inside := false
+	for _, e := range edges {
+		if e == skip || (e.a.Y > pt.Y) == (e.b.Y > pt.Y) {
+			continue
+		}
+		p1, p2 := e.a, e.b
+		if p2.Y < p1.Y {
+			p1, p2 = p2, p1
+		}
+		if Orient2D(p1, p2, pt) > 0 {
+			inside = !inside
+		}
+	}
+	return inside
+}
@@ -0,0 +1,66 @@
+// Copyright 2015 Simon HEGE. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package geom
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestMakeValid(t *testing.T) {
+	tests := []struct {
+		name string
+		g    Geometry
+		want Geometry
+	}{
+		{"valid polygon", box(0, 0, 10, 10), box(0, 0, 10, 10)},
+		{"bow-tie", Polygon{ring(0, 0, 10, 10, 10, 0, 0, 10)}, MultiPolygon{
+			Polygon{ring(0, 0, 5, 5, 0, 10)},
+			Polygon{ring(10, 10, 5, 5, 10, 0)},
+		}},
+		{"spike collapse", Polygon{ring(0, 0, 10, 0, 10, 10, 5, 10, 5, 15, 5, 10, 0, 10)}, GeometryCollection{
+			Polygon{ring(0, 0, 10, 0, 10, 10, 5, 10, 0, 10)},
+			LineString{{X: 5, Y: 10}, {X: 5, Y: 15}},
+		}},
+		{"repeated edge", Polygon{ring(0, 0, 10, 0, 10, 10, 0, 10, 0, 0, 10, 0)}, box(0, 0, 10, 10)},
+		{"reversed shell and hole", Polygon{ring(0, 0, 0, 10, 10, 10, 10, 0), ring(4, 4, 6, 4, 6, 6, 4, 6)}, Polygon{
+			ring(0, 10, 0, 0, 10, 0, 10, 10),
+			ring(6, 4, 4, 4, 4, 6, 6, 6),
+		}},
+		{"hole outside shell", Polygon{ring(0, 0, 10, 0, 10, 10, 0, 10), ring(20, 20, 22, 20, 22, 22)}, MultiPolygon{
+			box(0, 0, 10, 10),
+			Polygon{ring(20, 20, 22, 20, 22, 22)},
+		}},
+		{"overlapping polygons", MultiPolygon{box(0, 0, 2, 2), box(1, 1, 3, 3)}, MultiPolygon{
+			Polygon{ring(0, 0, 2, 0, 2, 1, 1, 1, 1, 2, 0, 2)},
+			Polygon{ring(2, 2, 2, 1, 3, 1, 3, 3, 1, 3, 1, 2)},
+		}},
+		{"collapsed polygon", Polygon{ring(0, 0, 1, 0, 1, 0)}, LineString{{X: 0, Y: 0}, {X: 1, Y: 0}}},
+		{"collapsed line", LineString{{X: 1, Y: 1}, {X: 1, Y: 1}, {X: math.NaN(), Y: 0}}, &Point{X: 1, Y: 1}},
+		{"Z bow-tie", PolygonZ{{{Point: Point{X: 0, Y: 0}, Z: 1}, {Point: Point{X: 10, Y: 10}, Z: 2}, {Point: Point{X: 10, Y: 0}, Z: 3}, {Point: Point{X: 0, Y: 10}, Z: 4}, {Point: Point{X: 0, Y: 0}, Z: 1}}}, MultiPolygonZ{
+			PolygonZ{{{Point: Point{X: 0, Y: 0}, Z: 1}, {Point: Point{X: 5, Y: 5}, Z: 2.5}, {Point: Point{X: 0, Y: 10}, Z: 4}, {Point: Point{X: 0, Y: 0}, Z: 1}}},
+			PolygonZ{{{Point: Point{X: 10, Y: 10}, Z: 2}, {Point: Point{X: 5, Y: 5}, Z: 2.5}, {Point: Point{X: 10, Y: 0}, Z: 3}, {Point: Point{X: 10, Y: 10}, Z: 2}}},
+		}},
+		{"collection", GeometryCollection{&Point{X: 1, Y: 1}, Polygon{ring(0, 0, 10, 10, 10, 0, 0, 10)}}, GeometryCollection{
+			&Point{X: 1, Y: 1},
+			MultiPolygon{Polygon{ring(0, 0, 5, 5, 0, 10)}, Polygon{ring(10, 10, 5, 5, 10, 0)}},
+		}},
+	}
+	for _, tt := range tests {
+		got, err := MakeValid(tt.g)
+		if err != nil || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: MakeValid() = %v, %v, want %v", tt.name, got, err, tt.want)
+			continue
+		}
+		if !IsValid(got) {
+			t.Errorf("%s: MakeValid() result is invalid: %v", tt.name, Validate(got))
+		}
+	}
+
+	if _, err := MakeValid(nil); err == nil {
+		t.Error("MakeValid(nil) should return an error")
+	}
+}
@@ -0,0 +1,141 @@
+// Copyright 2015 Simon HEGE. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package geom
+
+import (
+	"math"
+)
+
+//isCollection returns true if g is a heterogeneous geometry collection
+func isCollection(g Geometry) bool {
+	return g.GeometryType() == "GeometryCollection"
+}
+
+//pointsOf returns the points of a single-part geometry (a point or a line)
+func pointsOf(g Geometry) []Point {
+	var pts []Point
+	g.Iterate(func(p []Point) error {
+		pts = append(pts, p...)
+		return nil
+	})
+	return pts
+}
+
+//lineLength returns the planar length of a line
+func lineLength(points []Point) float64 {
+	length := 0.0
+	for i := 1; i < len(points); i++ {
+		length += math.Hypot(points[i].X-points[i-1].X, points[i].Y-points[i-1].Y)
+	}
+	return length
+}
+
+//ringArea returns the signed area of a ring: positive if counter-clockwise, negative if clockwise
+func ringArea(points []Point) float64 {
+	if len(points) < 3 {
+		return 0
+	}
+	//Shoelace formula, relative to the first point to limit rounding errors
+	area := 0.0
+	o := points[0]
+	for i := 1; i < len(points)-1; i++ {
+		area += (points[i].X-o.X)*(points[i+1].Y-o.Y) - (points[i+1].X-o.X)*(points[i].Y-o.Y)
+	}
+	return area / 2
+}
+
+//surfaces calls f on each surface of a geometry, recursing through geometry collections
+func surfaces(g Geometry, f func(s Surface)) {
+	if s, ok := g.(Surface); ok {
+		f(s)
+		return
+	}
+	if isCollection(g) || g.Dimension() == 2 {
+		for i := 0; i < g.NumGeometries(); i++ {
+			surfaces(g.GeometryN(i), f)
+		}
+	}
+}
+
+//Length returns the planar length of the linear geometries (LineString and MultiLineString).
+//Geometry collections are handled recursively; points and polygons have a length of 0.
+func Length(g Geometry) float64 {
+	if isCollection(g) {
+		length := 0.0
+		for i := 0; i < g.NumGeometries(); i++ {
+			length += Length(g.GeometryN(i))
+		}
+		return length
+	}
+	if g.Dimension() != 1 {
+		return 0
+	}
+
+	length := 0.0
+	g.Iterate(func(points []Point) error {
+		length += lineLength(points)
+		return nil
+	})
+	return length
+}
+
+//Length3D returns the length of the linear geometries, taking Z values into account.
+//Geometry collections are handled recursively; points and polygons have a length of 0.
+func Length3D(g GeometryZ) float64 {
+	if isCollection(g) {
+		length := 0.0
+		for i := 0; i < g.NumGeometries(); i++ {
+			if child, ok := g.GeometryN(i).(GeometryZ); ok {
+				length += Length3D(child)
+			}
+		}
+		return length
+	}
+	if g.Dimension() != 1 {
+		return 0
+	}
+
+	length := 0.0
+	g.IterateZ(func(points []PointZ) error {
+		for i := 1; i < len(points); i++ {
+			dx := points[i].X - points[i-1].X
+			dy := points[i].Y - points[i-1].Y
+			dz := points[i].Z - points[i-1].Z
+			length += math.Sqrt(dx*dx + dy*dy + dz*dz)
+		}
+		return nil
+	})
+	return length
+}
+
+//Area returns the planar area of the polygonal geometries (Polygon and MultiPolygon), holes being subtracted.
+//Geometry collections are handled recursively; points and lines have an area of 0.
+//The orientation of the rings is not significant.
+func Area(g Geometry) float64 {
+	area := 0.0
+	surfaces(g, func(s Surface) {
+		if s.IsEmpty() {
+			return
+		}
+		area += math.Abs(ringArea(pointsOf(s.ExteriorRing())))
+		for i := 0; i < s.NumInteriorRings(); i++ {
+			area -= math.Abs(ringArea(pointsOf(s.InteriorRingN(i))))
+		}
+	})
+	return area
+}
+
+//Perimeter returns the planar length of the boundary of the polygonal geometries (Polygon and MultiPolygon),
+//holes included. Geometry collections are handled recursively; points and lines have a perimeter of 0.
+func Perimeter(g Geometry) float64 {
+	perimeter := 0.0
+	surfaces(g, func(s Surface) {
+		s.Iterate(func(ring []Point) error {
+			perimeter += lineLength(ring)
+			return nil
+		})
+	})
+	return perimeter
+}
@@ -99,22 +99,66 @@ func (c MultiLineStringZM) EnvelopeZM() *EnvelopeZM {
 
 //Clone returns a deep copy of the multi-linestring
 func (c MultiLineString) Clone() Geometry {
-	return &c
+	clone := c.clone()
+	return &clone
+}
+
+//clone returns a deep copy of the multi-linestring, by value
+func (c MultiLineString) clone() MultiLineString {
+	clone := make(MultiLineString, len(c))
+	for i := range c {
+		clone[i] = make(LineString, len(c[i]))
+		copy(clone[i], c[i])
+	}
+	return clone
 }
 
 //Clone returns a deep copy of the multi-linestring
 func (c MultiLineStringZ) Clone() Geometry {
-	return &c
+	clone := c.clone()
+	return &clone
+}
+
+//clone returns a deep copy of the multi-linestring, by value
+func (c MultiLineStringZ) clone() MultiLineStringZ {
+	clone := make(MultiLineStringZ, len(c))
+	for i := range c {
+		clone[i] = make(LineStringZ, len(c[i]))
+		copy(clone[i], c[i])
+	}
+	return clone
 }
 
 //Clone returns a deep copy of the multi-linestring
 func (c MultiLineStringM) Clone() Geometry {
-	return &c
+	clone := c.clone()
+	return &clone
+}
+
+//clone returns a deep copy of the multi-linestring, by value
+func (c MultiLineStringM) clone() MultiLineStringM {
+	clone := make(MultiLineStringM, len(c))
+	for i := range c {
+		clone[i] = make(LineStringM, len(c[i]))
+		copy(clone[i], c[i])
+	}
+	return clone
 }
 
 //Clone returns a deep copy of the multi-linestring
 func (c MultiLineStringZM) Clone() Geometry {
-	return &c
+	clone := c.clone()
+	return &clone
+}
+
+//clone returns a deep copy of the multi-linestring, by value
+func (c MultiLineStringZM) clone() MultiLineStringZM {
+	clone := make(MultiLineStringZM, len(c))
+	for i := range c {
+		clone[i] = make(LineStringZM, len(c[i]))
+		copy(clone[i], c[i])
+	}
+	return clone
 }
 
 //Iterate walks over the points (and can modify in situ) the multi-linestring
@@ -156,3 +200,241 @@ func (c MultiLineStringZM) Iterate(f func([]Point) error) error {
 	}
 	return nil
 }
+
+//IterateZ walks over the points (and can modify in situ) the multi-linestring
+func (c MultiLineStringZ) IterateZ(f func([]PointZ) error) error {
+	for i := range c {
+		if err := c[i].IterateZ(f); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//IterateM walks over the points (and can modify in situ) the multi-linestring
+func (c MultiLineStringM) IterateM(f func([]PointM) error) error {
+	for i := range c {
+		if err := c[i].IterateM(f); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//IterateZ walks over the points (and can modify in situ) the multi-linestring
+func (c MultiLineStringZM) IterateZ(f func([]PointZ) error) error {
+	for i := range c {
+		if err := c[i].IterateZ(f); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//IterateM walks over the points (and can modify in situ) the multi-linestring
+func (c MultiLineStringZM) IterateM(f func([]PointM) error) error {
+	for i := range c {
+		if err := c[i].IterateM(f); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//IterateZM walks over the points (and can modify in situ) the multi-linestring
+func (c MultiLineStringZM) IterateZM(f func([]PointZM) error) error {
+	for i := range c {
+		if err := c[i].IterateZM(f); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//GeometryType returns the name of the geometry type
+func (c MultiLineString) GeometryType() string {
+	return "MultiLineString"
+}
+
+//Dimension returns the topological dimension of the multi-linestring: 1
+func (c MultiLineString) Dimension() int {
+	return 1
+}
+
+//CoordinateDimension returns the number of values of each coordinate
+func (c MultiLineString) CoordinateDimension() int {
+	return 2
+}
+
+//IsEmpty returns true if the multi-linestring has no points
+func (c MultiLineString) IsEmpty() bool {
+	for _, l := range c {
+		if len(l) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+//NumPoints returns the number of points of the multi-linestring
+func (c MultiLineString) NumPoints() int {
+	n := 0
+	for _, l := range c {
+		n += len(l)
+	}
+	return n
+}
+
+//NumGeometries returns the number of lines
+func (c MultiLineString) NumGeometries() int {
+	return len(c)
+}
+
+//GeometryN returns the n-th (zero based) line, or nil if n is out of range
+func (c MultiLineString) GeometryN(n int) Geometry {
+	if n < 0 || n >= len(c) {
+		return nil
+	}
+	return c[n]
+}
+
+//GeometryType returns the name of the geometry type
+func (c MultiLineStringZ) GeometryType() string {
+	return "MultiLineString"
+}
+
+//Dimension returns the topological dimension of the multi-linestring: 1
+func (c MultiLineStringZ) Dimension() int {
+	return 1
+}
+
+//CoordinateDimension returns the number of values of each coordinate
+func (c MultiLineStringZ) CoordinateDimension() int {
+	return 3
+}
+
+//IsEmpty returns true if the multi-linestring has no points
+func (c MultiLineStringZ) IsEmpty() bool {
+	for _, l := range c {
+		if len(l) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+//NumPoints returns the number of points of the multi-linestring
+func (c MultiLineStringZ) NumPoints() int {
+	n := 0
+	for _, l := range c {
+		n += len(l)
+	}
+	return n
+}
+
+//NumGeometries returns the number of lines
+func (c MultiLineStringZ) NumGeometries() int {
+	return len(c)
+}
+
+//GeometryN returns the n-th (zero based) line, or nil if n is out of range
+func (c MultiLineStringZ) GeometryN(n int) Geometry {
+	if n < 0 || n >= len(c) {
+		return nil
+	}
+	return c[n]
+}
+
+//GeometryType returns the name of the geometry type
+func (c MultiLineStringM) GeometryType() string {
+	return "MultiLineString"
+}
+
+//Dimension returns the topological dimension of the multi-linestring: 1
+func (c MultiLineStringM) Dimension() int {
+	return 1
+}
+
+//CoordinateDimension returns the number of values of each coordinate
+func (c MultiLineStringM) CoordinateDimension() int {
+	return 3
+}
+
+//IsEmpty returns true if the multi-linestring has no points
+func (c MultiLineStringM) IsEmpty() bool {
+	for _, l := range c {
+		if len(l) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+//NumPoints returns the number of points of the multi-linestring
+func (c MultiLineStringM) NumPoints() int {
+	n := 0
+	for _, l := range c {
+		n += len(l)
+	}
+	return n
+}
+
+//NumGeometries returns the number of lines
+func (c MultiLineStringM) NumGeometries() int {
+	return len(c)
+}
+
+//GeometryN returns the n-th (zero based) line, or nil if n is out of range
+func (c MultiLineStringM) GeometryN(n int) Geometry {
+	if n < 0 || n >= len(c) {
+		return nil
+	}
+	return c[n]
+}
+
+//GeometryType returns the name of the geometry type
+func (c MultiLineStringZM) GeometryType() string {
+	return "MultiLineString"
+}
+
+//Dimension returns the topological dimension of the multi-linestring: 1
+func (c MultiLineStringZM) Dimension() int {
+	return 1
+}
+
+//CoordinateDimension returns the number of values of each coordinate
+func (c MultiLineStringZM) CoordinateDimension() int {
+	return 4
+}
+
+//IsEmpty returns true if the multi-linestring has no points
+func (c MultiLineStringZM) IsEmpty() bool {
+	for _, l := range c {
+		if len(l) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+//NumPoints returns the number of points of the multi-linestring
+func (c MultiLineStringZM) NumPoints() int {
+	n := 0
+	for _, l := range c {
+		n += len(l)
+	}
+	return n
+}
+
+//NumGeometries returns the number of lines
+func (c MultiLineStringZM) NumGeometries() int {
+	return len(c)
+}
+
+//GeometryN returns the n-th (zero based) line, or nil if n is out of range
+func (c MultiLineStringZM) GeometryN(n int) Geometry {
+	if n < 0 || n >= len(c) {
+		return nil
+	}
+	return c[n]
+}
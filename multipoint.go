@@ -99,22 +99,54 @@ func (c MultiPointZM) EnvelopeZM() *EnvelopeZM {
 
 //Clone returns a deep copy of the multi-point
 func (c MultiPoint) Clone() Geometry {
-	return &c
+	clone := c.clone()
+	return &clone
+}
+
+//clone returns a deep copy of the multi-point, by value
+func (c MultiPoint) clone() MultiPoint {
+	clone := make(MultiPoint, len(c))
+	copy(clone, c)
+	return clone
 }
 
 //Clone returns a deep copy of the multi-point
 func (c MultiPointZ) Clone() Geometry {
-	return &c
+	clone := c.clone()
+	return &clone
+}
+
+//clone returns a deep copy of the multi-point, by value
+func (c MultiPointZ) clone() MultiPointZ {
+	clone := make(MultiPointZ, len(c))
+	copy(clone, c)
+	return clone
 }
 
 //Clone returns a deep copy of the multi-point
 func (c MultiPointM) Clone() Geometry {
-	return &c
+	clone := c.clone()
+	return &clone
+}
+
+//clone returns a deep copy of the multi-point, by value
+func (c MultiPointM) clone() MultiPointM {
+	clone := make(MultiPointM, len(c))
+	copy(clone, c)
+	return clone
 }
 
 //Clone returns a deep copy of the multi-point
 func (c MultiPointZM) Clone() Geometry {
-	return &c
+	clone := c.clone()
+	return &clone
+}
+
+//clone returns a deep copy of the multi-point, by value
+func (c MultiPointZM) clone() MultiPointZM {
+	clone := make(MultiPointZM, len(c))
+	copy(clone, c)
+	return clone
 }
 
 //Iterate walks over the points (and can modify in situ) the multi-point
@@ -160,3 +192,180 @@ func (c MultiPointZM) Iterate(f func([]Point) error) error {
 	}
 	return err
 }
+
+//IterateZ walks over the points (and can modify in situ) the multi-point
+func (c MultiPointZ) IterateZ(f func([]PointZ) error) error {
+	return f(c)
+}
+
+//IterateM walks over the points (and can modify in situ) the multi-point
+func (c MultiPointM) IterateM(f func([]PointM) error) error {
+	return f(c)
+}
+
+//IterateZ walks over the points (and can modify in situ) the multi-point
+func (c MultiPointZM) IterateZ(f func([]PointZ) error) error {
+	return LineStringZM(c).IterateZ(f)
+}
+
+//IterateM walks over the points (and can modify in situ) the multi-point
+func (c MultiPointZM) IterateM(f func([]PointM) error) error {
+	return LineStringZM(c).IterateM(f)
+}
+
+//IterateZM walks over the points (and can modify in situ) the multi-point
+func (c MultiPointZM) IterateZM(f func([]PointZM) error) error {
+	return f(c)
+}
+
+//GeometryType returns the name of the geometry type
+func (c MultiPoint) GeometryType() string {
+	return "MultiPoint"
+}
+
+//Dimension returns the topological dimension of the multi-point: 0
+func (c MultiPoint) Dimension() int {
+	return 0
+}
+
+//CoordinateDimension returns the number of values of each coordinate
+func (c MultiPoint) CoordinateDimension() int {
+	return 2
+}
+
+//IsEmpty returns true if the multi-point has no points
+func (c MultiPoint) IsEmpty() bool {
+	return len(c) == 0
+}
+
+//NumPoints returns the number of points of the multi-point
+func (c MultiPoint) NumPoints() int {
+	return len(c)
+}
+
+//NumGeometries returns the number of points
+func (c MultiPoint) NumGeometries() int {
+	return len(c)
+}
+
+//GeometryN returns the n-th (zero based) point, or nil if n is out of range
+func (c MultiPoint) GeometryN(n int) Geometry {
+	if n < 0 || n >= len(c) {
+		return nil
+	}
+	return &c[n]
+}
+
+//GeometryType returns the name of the geometry type
+func (c MultiPointZ) GeometryType() string {
+	return "MultiPoint"
+}
+
+//Dimension returns the topological dimension of the multi-point: 0
+func (c MultiPointZ) Dimension() int {
+	return 0
+}
+
+//CoordinateDimension returns the number of values of each coordinate
+func (c MultiPointZ) CoordinateDimension() int {
+	return 3
+}
+
+//IsEmpty returns true if the multi-point has no points
+func (c MultiPointZ) IsEmpty() bool {
+	return len(c) == 0
+}
+
+//NumPoints returns the number of points of the multi-point
+func (c MultiPointZ) NumPoints() int {
+	return len(c)
+}
+
+//NumGeometries returns the number of points
+func (c MultiPointZ) NumGeometries() int {
+	return len(c)
+}
+
+//GeometryN returns the n-th (zero based) point, or nil if n is out of range
+func (c MultiPointZ) GeometryN(n int) Geometry {
+	if n < 0 || n >= len(c) {
+		return nil
+	}
+	return &c[n]
+}
+
+//GeometryType returns the name of the geometry type
+func (c MultiPointM) GeometryType() string {
+	return "MultiPoint"
+}
+
+//Dimension returns the topological dimension of the multi-point: 0
+func (c MultiPointM) Dimension() int {
+	return 0
+}
+
+//CoordinateDimension returns the number of values of each coordinate
+func (c MultiPointM) CoordinateDimension() int {
+	return 3
+}
+
+//IsEmpty returns true if the multi-point has no points
+func (c MultiPointM) IsEmpty() bool {
+	return len(c) == 0
+}
+
+//NumPoints returns the number of points of the multi-point
+func (c MultiPointM) NumPoints() int {
+	return len(c)
+}
+
+//NumGeometries returns the number of points
+func (c MultiPointM) NumGeometries() int {
+	return len(c)
+}
+
+//GeometryN returns the n-th (zero based) point, or nil if n is out of range
+func (c MultiPointM) GeometryN(n int) Geometry {
+	if n < 0 || n >= len(c) {
+		return nil
+	}
+	return &c[n]
+}
+
+//GeometryType returns the name of the geometry type
+func (c MultiPointZM) GeometryType() string {
+	return "MultiPoint"
+}
+
+//Dimension returns the topological dimension of the multi-point: 0
+func (c MultiPointZM) Dimension() int {
+	return 0
+}
+
+//CoordinateDimension returns the number of values of each coordinate
+func (c MultiPointZM) CoordinateDimension() int {
+	return 4
+}
+
+//IsEmpty returns true if the multi-point has no points
+func (c MultiPointZM) IsEmpty() bool {
+	return len(c) == 0
+}
+
+//NumPoints returns the number of points of the multi-point
+func (c MultiPointZM) NumPoints() int {
+	return len(c)
+}
+
+//NumGeometries returns the number of points
+func (c MultiPointZM) NumGeometries() int {
+	return len(c)
+}
+
+//GeometryN returns the n-th (zero based) point, or nil if n is out of range
+func (c MultiPointZM) GeometryN(n int) Geometry {
+	if n < 0 || n >= len(c) {
+		return nil
+	}
+	return &c[n]
+}
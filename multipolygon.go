@@ -99,22 +99,62 @@ func (c MultiPolygonZM) EnvelopeZM() *EnvelopeZM {
 
 //Clone returns a deep copy of the multi-polygon
 func (c MultiPolygon) Clone() Geometry {
-	return &c
+	clone := c.clone()
+	return &clone
+}
+
+//clone returns a deep copy of the multi-polygon, by value
+func (c MultiPolygon) clone() MultiPolygon {
+	clone := make(MultiPolygon, len(c))
+	for i := range c {
+		clone[i] = c[i].clone()
+	}
+	return clone
 }
 
 //Clone returns a deep copy of the multi-polygon
 func (c MultiPolygonZ) Clone() Geometry {
-	return &c
+	clone := c.clone()
+	return &clone
+}
+
+//clone returns a deep copy of the multi-polygon, by value
+func (c MultiPolygonZ) clone() MultiPolygonZ {
+	clone := make(MultiPolygonZ, len(c))
+	for i := range c {
+		clone[i] = c[i].clone()
+	}
+	return clone
 }
 
 //Clone returns a deep copy of the multi-polygon
 func (c MultiPolygonM) Clone() Geometry {
-	return &c
+	clone := c.clone()
+	return &clone
+}
+
+//clone returns a deep copy of the multi-polygon, by value
+func (c MultiPolygonM) clone() MultiPolygonM {
+	clone := make(MultiPolygonM, len(c))
+	for i := range c {
+		clone[i] = c[i].clone()
+	}
+	return clone
 }
 
 //Clone returns a deep copy of the multi-polygon
 func (c MultiPolygonZM) Clone() Geometry {
-	return &c
+	clone := c.clone()
+	return &clone
+}
+
+//clone returns a deep copy of the multi-polygon, by value
+func (c MultiPolygonZM) clone() MultiPolygonZM {
+	clone := make(MultiPolygonZM, len(c))
+	for i := range c {
+		clone[i] = c[i].clone()
+	}
+	return clone
 }
 
 //Iterate walks over the points (and can modify in situ) the multi-polygon
@@ -156,3 +196,241 @@ func (c MultiPolygonZM) Iterate(f func([]Point) error) error {
 	}
 	return nil
 }
+
+//IterateZ walks over the points (and can modify in situ) the multi-polygon
+func (c MultiPolygonZ) IterateZ(f func([]PointZ) error) error {
+	for i := range c {
+		if err := c[i].IterateZ(f); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//IterateM walks over the points (and can modify in situ) the multi-polygon
+func (c MultiPolygonM) IterateM(f func([]PointM) error) error {
+	for i := range c {
+		if err := c[i].IterateM(f); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//IterateZ walks over the points (and can modify in situ) the multi-polygon
+func (c MultiPolygonZM) IterateZ(f func([]PointZ) error) error {
+	for i := range c {
+		if err := c[i].IterateZ(f); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//IterateM walks over the points (and can modify in situ) the multi-polygon
+func (c MultiPolygonZM) IterateM(f func([]PointM) error) error {
+	for i := range c {
+		if err := c[i].IterateM(f); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//IterateZM walks over the points (and can modify in situ) the multi-polygon
+func (c MultiPolygonZM) IterateZM(f func([]PointZM) error) error {
+	for i := range c {
+		if err := c[i].IterateZM(f); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//GeometryType returns the name of the geometry type
+func (c MultiPolygon) GeometryType() string {
+	return "MultiPolygon"
+}
+
+//Dimension returns the topological dimension of the multi-polygon: 2
+func (c MultiPolygon) Dimension() int {
+	return 2
+}
+
+//CoordinateDimension returns the number of values of each coordinate
+func (c MultiPolygon) CoordinateDimension() int {
+	return 2
+}
+
+//IsEmpty returns true if the multi-polygon has no non-empty polygon
+func (c MultiPolygon) IsEmpty() bool {
+	for _, p := range c {
+		if !p.IsEmpty() {
+			return false
+		}
+	}
+	return true
+}
+
+//NumPoints returns the number of points of the multi-polygon
+func (c MultiPolygon) NumPoints() int {
+	n := 0
+	for _, p := range c {
+		n += p.NumPoints()
+	}
+	return n
+}
+
+//NumGeometries returns the number of polygons
+func (c MultiPolygon) NumGeometries() int {
+	return len(c)
+}
+
+//GeometryN returns the n-th (zero based) polygon, or nil if n is out of range
+func (c MultiPolygon) GeometryN(n int) Geometry {
+	if n < 0 || n >= len(c) {
+		return nil
+	}
+	return c[n]
+}
+
+//GeometryType returns the name of the geometry type
+func (c MultiPolygonZ) GeometryType() string {
+	return "MultiPolygon"
+}
+
+//Dimension returns the topological dimension of the multi-polygon: 2
+func (c MultiPolygonZ) Dimension() int {
+	return 2
+}
+
+//CoordinateDimension returns the number of values of each coordinate
+func (c MultiPolygonZ) CoordinateDimension() int {
+	return 3
+}
+
+//IsEmpty returns true if the multi-polygon has no non-empty polygon
+func (c MultiPolygonZ) IsEmpty() bool {
+	for _, p := range c {
+		if !p.IsEmpty() {
+			return false
+		}
+	}
+	return true
+}
+
+//NumPoints returns the number of points of the multi-polygon
+func (c MultiPolygonZ) NumPoints() int {
+	n := 0
+	for _, p := range c {
+		n += p.NumPoints()
+	}
+	return n
+}
+
+//NumGeometries returns the number of polygons
+func (c MultiPolygonZ) NumGeometries() int {
+	return len(c)
+}
+
+//GeometryN returns the n-th (zero based) polygon, or nil if n is out of range
+func (c MultiPolygonZ) GeometryN(n int) Geometry {
+	if n < 0 || n >= len(c) {
+		return nil
+	}
+	return c[n]
+}
+
+//GeometryType returns the name of the geometry type
+func (c MultiPolygonM) GeometryType() string {
+	return "MultiPolygon"
+}
+
+//Dimension returns the topological dimension of the multi-polygon: 2
+func (c MultiPolygonM) Dimension() int {
+	return 2
+}
+
+//CoordinateDimension returns the number of values of each coordinate
+func (c MultiPolygonM) CoordinateDimension() int {
+	return 3
+}
+
+//IsEmpty returns true if the multi-polygon has no non-empty polygon
+func (c MultiPolygonM) IsEmpty() bool {
+	for _, p := range c {
+		if !p.IsEmpty() {
+			return false
+		}
+	}
+	return true
+}
+
+//NumPoints returns the number of points of the multi-polygon
+func (c MultiPolygonM) NumPoints() int {
+	n := 0
+	for _, p := range c {
+		n += p.NumPoints()
+	}
+	return n
+}
+
+//NumGeometries returns the number of polygons
+func (c MultiPolygonM) NumGeometries() int {
+	return len(c)
+}
+
+//GeometryN returns the n-th (zero based) polygon, or nil if n is out of range
+func (c MultiPolygonM) GeometryN(n int) Geometry {
+	if n < 0 || n >= len(c) {
+		return nil
+	}
+	return c[n]
+}
+
+//GeometryType returns the name of the geometry type
+func (c MultiPolygonZM) GeometryType() string {
+	return "MultiPolygon"
+}
+
+//Dimension returns the topological dimension of the multi-polygon: 2
+func (c MultiPolygonZM) Dimension() int {
+	return 2
+}
+
+//CoordinateDimension returns the number of values of each coordinate
+func (c MultiPolygonZM) CoordinateDimension() int {
+	return 4
+}
+
+//IsEmpty returns true if the multi-polygon has no non-empty polygon
+func (c MultiPolygonZM) IsEmpty() bool {
+	for _, p := range c {
+		if !p.IsEmpty() {
+			return false
+		}
+	}
+	return true
+}
+
+//NumPoints returns the number of points of the multi-polygon
+func (c MultiPolygonZM) NumPoints() int {
+	n := 0
+	for _, p := range c {
+		n += p.NumPoints()
+	}
+	return n
+}
+
+//NumGeometries returns the number of polygons
+func (c MultiPolygonZM) NumGeometries() int {
+	return len(c)
+}
+
+//GeometryN returns the n-th (zero based) polygon, or nil if n is out of range
+func (c MultiPolygonZM) GeometryN(n int) Geometry {
+	if n < 0 || n >= len(c) {
+		return nil
+	}
+	return c[n]
+}
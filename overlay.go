@@ -0,0 +1,525 @@
+// Copyright 2015 Simon HEGE. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package geom
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+)
+
+//OverlayOp is a set-theoretic operation between two geometries
+type OverlayOp int
+
+//Overlay operations
+const (
+	OpUnion OverlayOp = iota
+	OpIntersection
+	OpDifference
+	OpSymDifference
+)
+
+func (op OverlayOp) String() string {
+	switch op {
+	case OpUnion:
+		return "Union"
+	case OpIntersection:
+		return "Intersection"
+	case OpDifference:
+		return "Difference"
+	case OpSymDifference:
+		return "SymDifference"
+	}
+	return "Unknown"
+}
+
+//contains returns true if a point belongs to the result of the operation,
+//given whether it belongs to the first and to the second geometry
+func (op OverlayOp) contains(inA, inB bool) bool {
+	switch op {
+	case OpUnion:
+		return inA || inB
+	case OpIntersection:
+		return inA && inB
+	case OpDifference:
+		return inA && !inB
+	case OpSymDifference:
+		return inA != inB
+	}
+	return false
+}
+
+//Union returns the points belonging to a or b. See Overlay.
+func Union(a, b Geometry) (Geometry, error) {
+	return Overlay(a, b, OpUnion)
+}
+
+//Intersection returns the points belonging to both a and b. See Overlay.
+func Intersection(a, b Geometry) (Geometry, error) {
+	return Overlay(a, b, OpIntersection)
+}
+
+//Difference returns the points of a not belonging to b. See Overlay.
+func Difference(a, b Geometry) (Geometry, error) {
+	return Overlay(a, b, OpDifference)
+}
+
+//SymDifference returns the points belonging to either a or b, but not to both. See Overlay.
+func SymDifference(a, b Geometry) (Geometry, error) {
+	return Overlay(a, b, OpSymDifference)
+}
+
+//Overlay computes a set-theoretic operation between a and b.
+//Both geometries must be a LineString, MultiLineString, Polygon or MultiPolygon (any dimension variant),
+//and polygons are expected to be valid.
+//
+//The result is a Polygon or MultiPolygon, a LineString or MultiLineString, or a Point or MultiPoint.
+//When it has components of several dimensions (e.g. the intersection of two polygons sharing an edge
+//and a corner), they are returned in a GeometryCollection.
+//Polygon shells are counter-clockwise, holes clockwise. Lines are merged at nodes where exactly two result lines meet.
+//
+//If both a and b have Z values, the result has Z values: vertices keep their input Z (the one of a if shared),
+//and Z of new intersection vertices is interpolated along the input segments. M values are dropped.
+func Overlay(a, b Geometry, op OverlayOp) (Geometry, error) {
+	for _, g := range []Geometry{a, b} {
+		if g == nil {
+			return nil, fmt.Errorf("Unsupported geometry type: %v", reflect.TypeOf(g))
+		}
+		switch g.GeometryType() {
+		case "LineString", "MultiLineString", "Polygon", "MultiPolygon":
+		default:
+			return nil, fmt.Errorf("Unsupported geometry type: %v", reflect.TypeOf(g))
+		}
+	}
+
+	g := newTopologyGraph(a, b)
+	r := &overlayResult{z: overlayZ(g, a, b)}
+	r.build(g, op)
+
+	//Dimension of an empty result
+	dim := a.Dimension()
+	switch op {
+	case OpIntersection:
+		if b.Dimension() < dim {
+			dim = b.Dimension()
+		}
+	case OpUnion, OpSymDifference:
+		if b.Dimension() > dim {
+			dim = b.Dimension()
+		}
+	}
+	return r.geometry(dim), nil
+}
+
+//overlayZ returns the Z value of the nodes of the graph, or nil if a or b has no Z
+func overlayZ(g *topologyGraph, a, b Geometry) map[Point]float64 {
+	za, okA := a.(GeometryZ)
+	zb, okB := b.(GeometryZ)
+	if !okA || !okB {
+		return nil
+	}
+
+	z := make(map[Point]float64)
+	for _, gz := range []GeometryZ{za, zb} {
+		gz.IterateZ(func(points []PointZ) error {
+			for _, pt := range points {
+				if _, ok := z[pt.Point]; !ok {
+					z[pt.Point] = pt.Z
+				}
+			}
+			return nil
+		})
+	}
+
+	//Intersection nodes: average of the values interpolated along each segment
+	sum := make(map[Point]float64)
+	count := make(map[Point]int)
+	for _, s := range g.segments {
+		length := math.Hypot(s.b.X-s.a.X, s.b.Y-s.a.Y)
+		if length == 0 {
+			continue
+		}
+		za, zb := z[s.a], z[s.b]
+		for _, pt := range s.nodes {
+			if _, ok := z[pt]; ok {
+				continue
+			}
+			t := math.Hypot(pt.X-s.a.X, pt.Y-s.a.Y) / length
+			sum[pt] += za + t*(zb-za)
+			count[pt]++
+		}
+	}
+	for pt, n := range count {
+		z[pt] = sum[pt] / float64(n)
+	}
+	return z
+}
+
+//overlayResult holds the components of the result of an overlay operation
+type overlayResult struct {
+	polygons [][][]Point
+	lines    [][]Point
+	points   []Point
+	z        map[Point]float64 //nil if the result has no Z
+}
+
+//directedEdge is an edge of the result area boundary, with the area on its left
+type directedEdge struct {
+	from, to Point
+	visited  bool
+}
+
+//angleLess returns true if the direction from o to p comes before the direction from o to q,
+//counter-clockwise from the positive X axis
+func angleLess(o, p, q Point) bool {
+	upper := func(pt Point) bool {
+		return pt.Y > o.Y || (pt.Y == o.Y && pt.X > o.X)
+	}
+	if up, uq := upper(p), upper(q); up != uq {
+		return up
+	}
+	return Orient2D(o, p, q) > 0
+}
+
+//build selects the edges and nodes of the graph belonging to the result of op
+func (r *overlayResult) build(g *topologyGraph, op OverlayOp) {
+	inArea := func(l [2]Location) bool {
+		return op.contains(l[0] == Interior, l[1] == Interior)
+	}
+
+	var directed []*directedEdge
+	outgoing := make(map[Point][]*directedEdge)
+	var lineEdges []*edge
+	covered := make(map[Point]bool)
+	for _, e := range g.edges {
+		left, right := inArea(e.left), inArea(e.right)
+		var d *directedEdge
+		switch {
+		case left && !right:
+			d = &directedEdge{from: e.a, to: e.b}
+		case right && !left:
+			d = &directedEdge{from: e.b, to: e.a}
+		case !left && !right:
+			if !op.contains(e.loc[0] != Exterior, e.loc[1] != Exterior) {
+				continue
+			}
+			lineEdges = append(lineEdges, e)
+		}
+		if d != nil {
+			directed = append(directed, d)
+			outgoing[d.from] = append(outgoing[d.from], d)
+		}
+		covered[e.a], covered[e.b] = true, true
+	}
+
+	r.buildPolygons(directed, outgoing)
+	r.buildLines(lineEdges)
+
+	for _, n := range g.nodes {
+		if !covered[n.pt] && op.contains(n.loc[0] != Exterior, n.loc[1] != Exterior) {
+			r.points = append(r.points, n.pt)
+		}
+	}
+	sort.Slice(r.points, func(i, j int) bool {
+		return less(r.points[i], r.points[j])
+	})
+}
+
+//faceWalker sorts the outgoing edges around each node, and returns a function giving the edge following d
+//along the face on its left: the first outgoing edge clockwise from the reverse of d
+func faceWalker(outgoing map[Point][]*directedEdge) func(d *directedEdge) *directedEdge {
+	for pt, edges := range outgoing {
+		o := pt
+		sort.Slice(edges, func(i, j int) bool {
+			return angleLess(o, edges[i].to, edges[j].to)
+		})
+	}
+
+	return func(d *directedEdge) *directedEdge {
+		edges := outgoing[d.to]
+		for i := len(edges) - 1; i >= 0; i-- {
+			if angleLess(d.to, edges[i].to, d.from) {
+				return edges[i]
+			}
+		}
+		if len(edges) == 0 {
+			return nil
+		}
+		return edges[len(edges)-1]
+	}
+}
+
+//buildPolygons links the directed edges into rings, and assigns holes to their shell
+func (r *overlayResult) buildPolygons(directed []*directedEdge, outgoing map[Point][]*directedEdge) {
+	next := faceWalker(outgoing)
+
+	var shells, holes [][]Point
+	for _, start := range directed {
+		if start.visited {
+			continue
+		}
+		ring := []Point{start.from}
+		for d := start; d != nil && !d.visited; d = next(d) {
+			d.visited = true
+			ring = append(ring, d.to)
+		}
+		if ring[0] != ring[len(ring)-1] {
+			//Broken ring, only possible through rounding errors
+			continue
+		}
+		for _, ring := range splitRing(ring) {
+			switch area := ringArea(ring); {
+			case area > 0:
+				shells = append(shells, ring)
+			case area < 0:
+				holes = append(holes, ring)
+			}
+		}
+	}
+
+	areas := make([]float64, len(shells))
+	for i, shell := range shells {
+		areas[i] = ringArea(shell)
+		r.polygons = append(r.polygons, [][]Point{shell})
+	}
+	for _, hole := range holes {
+		best := -1
+		for i, shell := range shells {
+			if (best < 0 || areas[i] < areas[best]) && ringContains(shell, hole) {
+				best = i
+			}
+		}
+		if best >= 0 {
+			r.polygons[best] = append(r.polygons[best], hole)
+		}
+	}
+}
+
+//splitRing splits a closed ring at its self-touching points, returning simple closed rings
+func splitRing(ring []Point) [][]Point {
+	var rings [][]Point
+	var stack []Point
+	index := make(map[Point]int)
+	for _, pt := range ring {
+		i, ok := index[pt]
+		if !ok {
+			index[pt] = len(stack)
+			stack = append(stack, pt)
+			continue
+		}
+		sub := make([]Point, 0, len(stack)-i+1)
+		sub = append(append(sub, stack[i:]...), pt)
+		rings = append(rings, sub)
+		for _, p := range stack[i+1:] {
+			delete(index, p)
+		}
+		stack = stack[:i+1]
+	}
+	return rings
+}
+
+//ringContains returns true if the ring inner lies inside the ring outer. Rings are expected not to cross.
+func ringContains(outer, inner []Point) bool {
+	for _, pt := range inner {
+		switch locateInRing(pt, outer) {
+		case Interior:
+			return true
+		case Exterior:
+			return false
+		}
+	}
+	//All the vertices are on the outer ring: test the middle of a segment
+	mid := Point{X: (inner[0].X + inner[1].X) / 2, Y: (inner[0].Y + inner[1].Y) / 2}
+	return locateInRing(mid, outer) != Exterior
+}
+
+//buildLines merges the line edges into lines, at nodes where exactly two of them meet
+func (r *overlayResult) buildLines(edges []*edge) {
+	adjacent := make(map[Point][]*edge)
+	for _, e := range edges {
+		adjacent[e.a] = append(adjacent[e.a], e)
+		adjacent[e.b] = append(adjacent[e.b], e)
+	}
+
+	visited := make(map[*edge]bool)
+	walk := func(from Point, e *edge) {
+		first := e
+		line := []Point{from}
+		for {
+			visited[e] = true
+			to := e.a
+			if to == from {
+				to = e.b
+			}
+			line = append(line, to)
+			next := adjacent[to]
+			if len(next) != 2 {
+				break
+			}
+			previous := e
+			e, from = next[0], to
+			if e == previous {
+				e = next[1]
+			}
+			if visited[e] {
+				break
+			}
+		}
+
+		//Follow the direction of the input line
+		l := first.labels[0]
+		if !l.line {
+			l = first.labels[1]
+		}
+		if l.forward != (line[0] == first.a) {
+			for i, j := 0, len(line)-1; i < j; i, j = i+1, j-1 {
+				line[i], line[j] = line[j], line[i]
+			}
+		}
+		r.lines = append(r.lines, line)
+	}
+
+	//Lines between nodes, then closed lines
+	for _, e := range edges {
+		for _, pt := range []Point{e.a, e.b} {
+			if !visited[e] && len(adjacent[pt]) != 2 {
+				walk(pt, e)
+			}
+		}
+	}
+	for _, e := range edges {
+		if !visited[e] {
+			walk(e.a, e)
+		}
+	}
+}
+
+//geometry returns the result as a geometry, or an empty geometry of dimension dim
+func (r *overlayResult) geometry(dim int) Geometry {
+	var parts []Geometry
+	if len(r.polygons) > 0 {
+		parts = append(parts, r.polygonal())
+	}
+	if len(r.lines) > 0 {
+		parts = append(parts, r.lineal())
+	}
+	if len(r.points) > 0 {
+		parts = append(parts, r.puntal())
+	}
+
+	switch len(parts) {
+	case 0:
+		if dim == 2 {
+			if r.z != nil {
+				return PolygonZ{}
+			}
+			return Polygon{}
+		}
+		if r.z != nil {
+			return LineStringZ{}
+		}
+		return LineString{}
+	case 1:
+		return parts[0]
+	}
+	if r.z != nil {
+		c := make(GeometryCollectionZ, len(parts))
+		for i := range parts {
+			c[i] = parts[i].(GeometryZ)
+		}
+		return c
+	}
+	return GeometryCollection(parts)
+}
+
+func (r *overlayResult) pointZ(pt Point) PointZ {
+	return PointZ{Point: pt, Z: r.z[pt]}
+}
+
+func (r *overlayResult) lineZ(points []Point) LineStringZ {
+	l := make(LineStringZ, len(points))
+	for i, pt := range points {
+		l[i] = r.pointZ(pt)
+	}
+	return l
+}
+
+func (r *overlayResult) polygonZ(rings [][]Point) PolygonZ {
+	p := make(PolygonZ, len(rings))
+	for i, ring := range rings {
+		p[i] = r.lineZ(ring)
+	}
+	return p
+}
+
+func polygon(rings [][]Point) Polygon {
+	p := make(Polygon, len(rings))
+	for i, ring := range rings {
+		p[i] = LineString(ring)
+	}
+	return p
+}
+
+func (r *overlayResult) polygonal() Geometry {
+	if r.z != nil {
+		if len(r.polygons) == 1 {
+			return r.polygonZ(r.polygons[0])
+		}
+		mp := make(MultiPolygonZ, len(r.polygons))
+		for i := range r.polygons {
+			mp[i] = r.polygonZ(r.polygons[i])
+		}
+		return mp
+	}
+	if len(r.polygons) == 1 {
+		return polygon(r.polygons[0])
+	}
+	mp := make(MultiPolygon, len(r.polygons))
+	for i := range r.polygons {
+		mp[i] = polygon(r.polygons[i])
+	}
+	return mp
+}
+
+func (r *overlayResult) lineal() Geometry {
+	if r.z != nil {
+		if len(r.lines) == 1 {
+			return r.lineZ(r.lines[0])
+		}
+		ml := make(MultiLineStringZ, len(r.lines))
+		for i := range r.lines {
+			ml[i] = r.lineZ(r.lines[i])
+		}
+		return ml
+	}
+	if len(r.lines) == 1 {
+		return LineString(r.lines[0])
+	}
+	ml := make(MultiLineString, len(r.lines))
+	for i := range r.lines {
+		ml[i] = LineString(r.lines[i])
+	}
+	return ml
+}
+
+func (r *overlayResult) puntal() Geometry {
+	if r.z != nil {
+		if len(r.points) == 1 {
+			pt := r.pointZ(r.points[0])
+			return &pt
+		}
+		mp := make(MultiPointZ, len(r.points))
+		for i := range r.points {
+			mp[i] = r.pointZ(r.points[i])
+		}
+		return mp
+	}
+	if len(r.points) == 1 {
+		pt := r.points[0]
+		return &pt
+	}
+	return MultiPoint(r.points)
+}
@@ -0,0 +1,140 @@
+// Copyright 2015 Simon HEGE. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package geom
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestOverlay(t *testing.T) {
+	withHole := Polygon{ring(0, 0, 10, 0, 10, 10, 0, 10), ring(4, 4, 4, 6, 6, 6, 6, 4)}
+	line := LineString{{X: -1, Y: 1}, {X: 3, Y: 1}}
+	tests := []struct {
+		name string
+		a, b Geometry
+		op   OverlayOp
+		want Geometry
+	}{
+		//Overlap
+		{"overlap union", box(0, 0, 2, 2), box(1, 1, 3, 3), OpUnion, Polygon{ring(0, 0, 2, 0, 2, 1, 3, 1, 3, 3, 1, 3, 1, 2, 0, 2)}},
+		{"overlap intersection", box(0, 0, 2, 2), box(1, 1, 3, 3), OpIntersection, box(1, 1, 2, 2)},
+		{"overlap difference", box(0, 0, 2, 2), box(1, 1, 3, 3), OpDifference, Polygon{ring(0, 0, 2, 0, 2, 1, 1, 1, 1, 2, 0, 2)}},
+		{"overlap symmetric difference", box(0, 0, 2, 2), box(1, 1, 3, 3), OpSymDifference, MultiPolygon{
+			Polygon{ring(0, 0, 2, 0, 2, 1, 1, 1, 1, 2, 0, 2)},
+			Polygon{ring(2, 2, 2, 1, 3, 1, 3, 3, 1, 3, 1, 2)},
+		}},
+		//Containment
+		{"containment union", box(0, 0, 10, 10), box(4, 4, 6, 6), OpUnion, box(0, 0, 10, 10)},
+		{"containment intersection", box(0, 0, 10, 10), box(4, 4, 6, 6), OpIntersection, box(4, 4, 6, 6)},
+		{"containment difference", box(0, 0, 10, 10), box(4, 4, 6, 6), OpDifference, withHole},
+		{"containment reverse difference", box(4, 4, 6, 6), box(0, 0, 10, 10), OpDifference, Polygon{}},
+		//Touching
+		{"edge touch union", box(0, 0, 2, 2), box(2, 0, 4, 2), OpUnion, box(0, 0, 4, 2)},
+		{"edge touch intersection", box(0, 0, 2, 2), box(2, 0, 4, 2), OpIntersection, LineString{{X: 2, Y: 2}, {X: 2, Y: 0}}},
+		{"edge touch difference", box(0, 0, 2, 2), box(2, 0, 4, 2), OpDifference, box(0, 0, 2, 2)},
+		{"corner touch union", box(0, 0, 2, 2), box(2, 2, 4, 4), OpUnion, MultiPolygon{box(0, 0, 2, 2), box(2, 2, 4, 4)}},
+		{"corner touch intersection", box(0, 0, 2, 2), box(2, 2, 4, 4), OpIntersection, &Point{X: 2, Y: 2}},
+		{"disjoint intersection", box(0, 0, 2, 2), box(5, 5, 6, 6), OpIntersection, Polygon{}},
+		//Holes
+		{"hole union", withHole, box(3, 3, 7, 7), OpUnion, box(0, 0, 10, 10)},
+		{"hole intersection", withHole, box(3, 3, 7, 7), OpIntersection, Polygon{ring(3, 3, 7, 3, 7, 7, 3, 7), ring(4, 4, 4, 6, 6, 6, 6, 4)}},
+		{"hole difference", withHole, box(3, 3, 7, 7), OpDifference, Polygon{ring(0, 0, 10, 0, 10, 10, 0, 10), ring(3, 3, 3, 7, 7, 7, 7, 3)}},
+		{"hole symmetric difference", withHole, box(3, 3, 7, 7), OpSymDifference, MultiPolygon{
+			Polygon{ring(0, 0, 10, 0, 10, 10, 0, 10), ring(3, 3, 3, 7, 7, 7, 7, 3)},
+			box(4, 4, 6, 6),
+		}},
+		{"hole filled union", withHole, box(4, 4, 6, 6), OpUnion, box(0, 0, 10, 10)},
+		//Collinear edges
+		{"collinear union", box(0, 0, 2, 1), box(1, 0, 3, 1), OpUnion, box(0, 0, 3, 1)},
+		{"collinear intersection", box(0, 0, 2, 1), box(1, 0, 3, 1), OpIntersection, box(1, 0, 2, 1)},
+		{"collinear difference", box(0, 0, 2, 1), box(1, 0, 3, 1), OpDifference, box(0, 0, 1, 1)},
+		{"collinear lines union", LineString{{X: 0, Y: 0}, {X: 4, Y: 0}}, LineString{{X: 2, Y: 0}, {X: 6, Y: 0}}, OpUnion, LineString{{X: 0, Y: 0}, {X: 6, Y: 0}}},
+		{"collinear lines intersection", LineString{{X: 0, Y: 0}, {X: 4, Y: 0}}, LineString{{X: 2, Y: 0}, {X: 6, Y: 0}}, OpIntersection, LineString{{X: 2, Y: 0}, {X: 4, Y: 0}}},
+		{"collinear lines symmetric difference", LineString{{X: 0, Y: 0}, {X: 4, Y: 0}}, LineString{{X: 2, Y: 0}, {X: 6, Y: 0}}, OpSymDifference, MultiLineString{{{X: 0, Y: 0}, {X: 2, Y: 0}}, {{X: 4, Y: 0}, {X: 6, Y: 0}}}},
+		//Lines and polygons
+		{"line intersection", box(0, 0, 2, 2), line, OpIntersection, LineString{{X: 0, Y: 1}, {X: 2, Y: 1}}},
+		{"line difference", line, box(0, 0, 2, 2), OpDifference, MultiLineString{{{X: -1, Y: 1}, {X: 0, Y: 1}}, {{X: 2, Y: 1}, {X: 3, Y: 1}}}},
+		{"polygon minus line", box(0, 0, 2, 2), line, OpDifference, box(0, 0, 2, 2)},
+		{"crossing lines intersection", LineString{{X: 0, Y: 0}, {X: 2, Y: 2}}, LineString{{X: 0, Y: 2}, {X: 2, Y: 0}}, OpIntersection, &Point{X: 1, Y: 1}},
+	}
+	for _, tt := range tests {
+		got, err := Overlay(tt.a, tt.b, tt.op)
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if reflect.TypeOf(got) != reflect.TypeOf(tt.want) {
+			t.Errorf("%s: Overlay() = %T %v, want %T %v", tt.name, got, got, tt.want, tt.want)
+			continue
+		}
+		if got.IsEmpty() != tt.want.IsEmpty() || (!got.IsEmpty() && !Equals(got, tt.want)) {
+			t.Errorf("%s: Overlay() = %v, want %v", tt.name, got, tt.want)
+		}
+		if a, b := Area(got), Area(tt.want); math.Abs(a-b) > 1e-9 {
+			t.Errorf("%s: area = %v, want %v", tt.name, a, b)
+		}
+	}
+}
+
+func TestOverlayZ(t *testing.T) {
+	a := PolygonZ{{{Point: Point{X: 0, Y: 0}}, {Point: Point{X: 2, Y: 0}}, {Point: Point{X: 2, Y: 2}, Z: 2}, {Point: Point{X: 0, Y: 2}, Z: 2}, {Point: Point{X: 0, Y: 0}}}}
+	b := LineStringZ{{Point: Point{X: -1, Y: 1}, Z: 10}, {Point: Point{X: 3, Y: 1}, Z: 20}}
+
+	//New vertices: mean of the Z interpolated along the segments of a and b
+	got, err := Intersection(a, b)
+	want := LineStringZ{{Point: Point{X: 0, Y: 1}, Z: (1 + 12.5) / 2}, {Point: Point{X: 2, Y: 1}, Z: (1 + 17.5) / 2}}
+	if err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("Intersection() = %v, %v, want %v", got, err, want)
+	}
+
+	//Vertices of the inputs keep their Z
+	c := PolygonZ{{{Point: Point{X: 1, Y: 1}, Z: 5}, {Point: Point{X: 3, Y: 1}, Z: 5}, {Point: Point{X: 3, Y: 3}, Z: 5}, {Point: Point{X: 1, Y: 3}, Z: 5}, {Point: Point{X: 1, Y: 1}, Z: 5}}}
+	got, err = Intersection(a, c)
+	p, ok := got.(PolygonZ)
+	if err != nil || !ok || len(p) != 1 {
+		t.Fatalf("Intersection() = %v, %v, want a PolygonZ", got, err)
+	}
+	wantZ := map[Point]float64{{X: 1, Y: 1}: 5, {X: 2, Y: 2}: 2, {X: 2, Y: 1}: 3, {X: 1, Y: 2}: 3.5}
+	for _, pt := range p[0] {
+		if z, ok := wantZ[pt.Point]; !ok || z != pt.Z {
+			t.Errorf("Intersection(): vertex %v, want Z %v", pt, z)
+		}
+	}
+
+	//Without Z on both sides, the result has no Z
+	if got, err := Intersection(a, box(1, 1, 3, 3)); err != nil || reflect.TypeOf(got) != reflect.TypeOf(Polygon{}) {
+		t.Errorf("Intersection() = %T, %v, want a Polygon", got, err)
+	}
+}
+
+func TestOverlaySnapping(t *testing.T) {
+	//Vertices closer than the snapping tolerance are merged: no sliver is created
+	a := box(0, 0, 10, 10)
+	b := Polygon{ring(10-1e-13, 0, 20, 0, 20, 10, 10+1e-13, 10)}
+	got, err := Union(a, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := got.(Polygon); !ok || math.Abs(Area(got)-200) > 1e-9 {
+		t.Errorf("Union() = %v, want a single polygon of area 200", got)
+	}
+	got, err = Intersection(a, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if Area(got) != 0 {
+		t.Errorf("Intersection() = %v, want no area", got)
+	}
+}
+
+func TestOverlayUnsupported(t *testing.T) {
+	for _, g := range []Geometry{nil, &Point{}, MultiPoint{{X: 1, Y: 1}}, GeometryCollection{}} {
+		if _, err := Union(g, box(0, 0, 1, 1)); err == nil {
+			t.Errorf("Union(%v) should return an error", g)
+		}
+	}
+}
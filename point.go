@@ -4,6 +4,10 @@
 
 package geom
 
+import (
+	"math"
+)
+
 //Point is a two-dimensional geometry representing a point
 type Point struct {
 	X float64
@@ -58,6 +62,21 @@ func (pt Point) Clone() Geometry {
 	return &pt
 }
 
+//Clone returns a deep copy of the point
+func (pt PointZ) Clone() Geometry {
+	return &pt
+}
+
+//Clone returns a deep copy of the point
+func (pt PointM) Clone() Geometry {
+	return &pt
+}
+
+//Clone returns a deep copy of the point
+func (pt PointZM) Clone() Geometry {
+	return &pt
+}
+
 //Iterate walks over the points (and can modify in situ) the point
 func (pt *Point) Iterate(f func([]Point) error) error {
 	points := []Point{*pt}
@@ -65,3 +84,116 @@ func (pt *Point) Iterate(f func([]Point) error) error {
 	*pt = points[0]
 	return err
 }
+
+//IterateZ walks over the points (and can modify in situ) the point
+func (pt *PointZ) IterateZ(f func([]PointZ) error) error {
+	points := []PointZ{*pt}
+	err := f(points)
+	*pt = points[0]
+	return err
+}
+
+//IterateM walks over the points (and can modify in situ) the point
+func (pt *PointM) IterateM(f func([]PointM) error) error {
+	points := []PointM{*pt}
+	err := f(points)
+	*pt = points[0]
+	return err
+}
+
+//IterateM walks over the points (and can modify in situ) the point
+func (pt *PointZM) IterateM(f func([]PointM) error) error {
+	points := []PointM{{Point: pt.Point, M: pt.M}}
+	err := f(points)
+	pt.Point = points[0].Point
+	pt.M = points[0].M
+	return err
+}
+
+//IterateZM walks over the points (and can modify in situ) the point
+func (pt *PointZM) IterateZM(f func([]PointZM) error) error {
+	points := []PointZM{*pt}
+	err := f(points)
+	*pt = points[0]
+	return err
+}
+
+//GeometryType returns the name of the geometry type
+func (pt Point) GeometryType() string {
+	return "Point"
+}
+
+//Dimension returns the topological dimension of the point: 0
+func (pt Point) Dimension() int {
+	return 0
+}
+
+//CoordinateDimension returns the number of values of each coordinate
+func (pt Point) CoordinateDimension() int {
+	return 2
+}
+
+//IsEmpty returns true if the point coordinates are NaN
+func (pt Point) IsEmpty() bool {
+	return math.IsNaN(pt.X) && math.IsNaN(pt.Y)
+}
+
+//NumPoints returns the number of points: 1, or 0 if the point is empty
+func (pt Point) NumPoints() int {
+	if pt.IsEmpty() {
+		return 0
+	}
+	return 1
+}
+
+//NumGeometries returns the number of geometries: 1
+func (pt Point) NumGeometries() int {
+	return 1
+}
+
+//GeometryN returns the point itself if n is 0, nil otherwise
+func (pt *Point) GeometryN(n int) Geometry {
+	if n != 0 {
+		return nil
+	}
+	return pt
+}
+
+//CoordinateDimension returns the number of values of each coordinate
+func (pt PointZ) CoordinateDimension() int {
+	return 3
+}
+
+//GeometryN returns the point itself if n is 0, nil otherwise
+func (pt *PointZ) GeometryN(n int) Geometry {
+	if n != 0 {
+		return nil
+	}
+	return pt
+}
+
+//CoordinateDimension returns the number of values of each coordinate
+func (pt PointM) CoordinateDimension() int {
+	return 3
+}
+
+//GeometryN returns the point itself if n is 0, nil otherwise
+func (pt *PointM) GeometryN(n int) Geometry {
+	if n != 0 {
+		return nil
+	}
+	return pt
+}
+
+//CoordinateDimension returns the number of values of each coordinate
+func (pt PointZM) CoordinateDimension() int {
+	return 4
+}
+
+//GeometryN returns the point itself if n is 0, nil otherwise
+func (pt *PointZM) GeometryN(n int) Geometry {
+	if n != 0 {
+		return nil
+	}
+	return pt
+}
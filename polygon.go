@@ -99,22 +99,66 @@ func (p PolygonZM) EnvelopeZM() *EnvelopeZM {
 
 //Clone returns a deep copy of the polygon
 func (p Polygon) Clone() Geometry {
-	return &p
+	clone := p.clone()
+	return &clone
+}
+
+//clone returns a deep copy of the polygon, by value
+func (p Polygon) clone() Polygon {
+	clone := make(Polygon, len(p))
+	for i := range p {
+		clone[i] = make(LineString, len(p[i]))
+		copy(clone[i], p[i])
+	}
+	return clone
 }
 
 //Clone returns a deep copy of the polygon
 func (p PolygonZ) Clone() Geometry {
-	return &p
+	clone := p.clone()
+	return &clone
+}
+
+//clone returns a deep copy of the polygon, by value
+func (p PolygonZ) clone() PolygonZ {
+	clone := make(PolygonZ, len(p))
+	for i := range p {
+		clone[i] = make(LineStringZ, len(p[i]))
+		copy(clone[i], p[i])
+	}
+	return clone
 }
 
 //Clone returns a deep copy of the polygon
 func (p PolygonM) Clone() Geometry {
-	return &p
+	clone := p.clone()
+	return &clone
+}
+
+//clone returns a deep copy of the polygon, by value
+func (p PolygonM) clone() PolygonM {
+	clone := make(PolygonM, len(p))
+	for i := range p {
+		clone[i] = make(LineStringM, len(p[i]))
+		copy(clone[i], p[i])
+	}
+	return clone
 }
 
 //Clone returns a deep copy of the polygon
 func (p PolygonZM) Clone() Geometry {
-	return &p
+	clone := p.clone()
+	return &clone
+}
+
+//clone returns a deep copy of the polygon, by value
+func (p PolygonZM) clone() PolygonZM {
+	clone := make(PolygonZM, len(p))
+	for i := range p {
+		clone[i] = make(LineStringZM, len(p[i]))
+		copy(clone[i], p[i])
+	}
+	return clone
 }
 
 //Iterate walks over the points (and can modify in situ) the polygon
@@ -156,3 +200,317 @@ func (p PolygonZM) Iterate(f func([]Point) error) error {
 	}
 	return nil
 }
+
+//IterateZ walks over the points (and can modify in situ) the polygon
+func (p PolygonZ) IterateZ(f func([]PointZ) error) error {
+	for i := range p {
+		if err := p[i].IterateZ(f); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//IterateM walks over the points (and can modify in situ) the polygon
+func (p PolygonM) IterateM(f func([]PointM) error) error {
+	for i := range p {
+		if err := p[i].IterateM(f); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//IterateZ walks over the points (and can modify in situ) the polygon
+func (p PolygonZM) IterateZ(f func([]PointZ) error) error {
+	for i := range p {
+		if err := p[i].IterateZ(f); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//IterateM walks over the points (and can modify in situ) the polygon
+func (p PolygonZM) IterateM(f func([]PointM) error) error {
+	for i := range p {
+		if err := p[i].IterateM(f); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//IterateZM walks over the points (and can modify in situ) the polygon
+func (p PolygonZM) IterateZM(f func([]PointZM) error) error {
+	for i := range p {
+		if err := p[i].IterateZM(f); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//GeometryType returns the name of the geometry type
+func (p Polygon) GeometryType() string {
+	return "Polygon"
+}
+
+//Dimension returns the topological dimension of the polygon: 2
+func (p Polygon) Dimension() int {
+	return 2
+}
+
+//CoordinateDimension returns the number of values of each coordinate
+func (p Polygon) CoordinateDimension() int {
+	return 2
+}
+
+//IsEmpty returns true if the polygon has no exterior ring
+func (p Polygon) IsEmpty() bool {
+	return len(p) == 0 || len(p[0]) == 0
+}
+
+//NumPoints returns the number of points of the polygon
+func (p Polygon) NumPoints() int {
+	n := 0
+	for _, ring := range p {
+		n += len(ring)
+	}
+	return n
+}
+
+//NumGeometries returns the number of geometries: 1
+func (p Polygon) NumGeometries() int {
+	return 1
+}
+
+//GeometryN returns the polygon itself if n is 0, nil otherwise
+func (p Polygon) GeometryN(n int) Geometry {
+	if n != 0 {
+		return nil
+	}
+	return p
+}
+
+//ExteriorRing returns the exterior ring of the polygon, or nil if the polygon has no ring
+func (p Polygon) ExteriorRing() Curve {
+	if len(p) == 0 {
+		return nil
+	}
+	return p[0]
+}
+
+//NumInteriorRings returns the number of holes of the polygon
+func (p Polygon) NumInteriorRings() int {
+	if len(p) == 0 {
+		return 0
+	}
+	return len(p) - 1
+}
+
+//InteriorRingN returns the n-th (zero based) hole of the polygon, or nil if n is out of range
+func (p Polygon) InteriorRingN(n int) Curve {
+	if n < 0 || n+1 >= len(p) {
+		return nil
+	}
+	return p[n+1]
+}
+
+//GeometryType returns the name of the geometry type
+func (p PolygonZ) GeometryType() string {
+	return "Polygon"
+}
+
+//Dimension returns the topological dimension of the polygon: 2
+func (p PolygonZ) Dimension() int {
+	return 2
+}
+
+//CoordinateDimension returns the number of values of each coordinate
+func (p PolygonZ) CoordinateDimension() int {
+	return 3
+}
+
+//IsEmpty returns true if the polygon has no exterior ring
+func (p PolygonZ) IsEmpty() bool {
+	return len(p) == 0 || len(p[0]) == 0
+}
+
+//NumPoints returns the number of points of the polygon
+func (p PolygonZ) NumPoints() int {
+	n := 0
+	for _, ring := range p {
+		n += len(ring)
+	}
+	return n
+}
+
+//NumGeometries returns the number of geometries: 1
+func (p PolygonZ) NumGeometries() int {
+	return 1
+}
+
+//GeometryN returns the polygon itself if n is 0, nil otherwise
+func (p PolygonZ) GeometryN(n int) Geometry {
+	if n != 0 {
+		return nil
+	}
+	return p
+}
+
+//ExteriorRing returns the exterior ring of the polygon, or nil if the polygon has no ring
+func (p PolygonZ) ExteriorRing() Curve {
+	if len(p) == 0 {
+		return nil
+	}
+	return p[0]
+}
+
+//NumInteriorRings returns the number of holes of the polygon
+func (p PolygonZ) NumInteriorRings() int {
+	if len(p) == 0 {
+		return 0
+	}
+	return len(p) - 1
+}
+
+//InteriorRingN returns the n-th (zero based) hole of the polygon, or nil if n is out of range
+func (p PolygonZ) InteriorRingN(n int) Curve {
+	if n < 0 || n+1 >= len(p) {
+		return nil
+	}
+	return p[n+1]
+}
+
+//GeometryType returns the name of the geometry type
+func (p PolygonM) GeometryType() string {
+	return "Polygon"
+}
+
+//Dimension returns the topological dimension of the polygon: 2
+func (p PolygonM) Dimension() int {
+	return 2
+}
+
+//CoordinateDimension returns the number of values of each coordinate
+func (p PolygonM) CoordinateDimension() int {
+	return 3
+}
+
+//IsEmpty returns true if the polygon has no exterior ring
+func (p PolygonM) IsEmpty() bool {
+	return len(p) == 0 || len(p[0]) == 0
+}
+
+//NumPoints returns the number of points of the polygon
+func (p PolygonM) NumPoints() int {
+	n := 0
+	for _, ring := range p {
+		n += len(ring)
+	}
+	return n
+}
+
+//NumGeometries returns the number of geometries: 1
+func (p PolygonM) NumGeometries() int {
+	return 1
+}
+
+//GeometryN returns the polygon itself if n is 0, nil otherwise
+func (p PolygonM) GeometryN(n int) Geometry {
+	if n != 0 {
+		return nil
+	}
+	return p
+}
+
+//ExteriorRing returns the exterior ring of the polygon, or nil if the polygon has no ring
+func (p PolygonM) ExteriorRing() Curve {
+	if len(p) == 0 {
+		return nil
+	}
+	return p[0]
+}
+
+//NumInteriorRings returns the number of holes of the polygon
+func (p PolygonM) NumInteriorRings() int {
+	if len(p) == 0 {
+		return 0
+	}
+	return len(p) - 1
+}
+
+//InteriorRingN returns the n-th (zero based) hole of the polygon, or nil if n is out of range
+func (p PolygonM) InteriorRingN(n int) Curve {
+	if n < 0 || n+1 >= len(p) {
+		return nil
+	}
+	return p[n+1]
+}
+
+//GeometryType returns the name of the geometry type
+func (p PolygonZM) GeometryType() string {
+	return "Polygon"
+}
+
+//Dimension returns the topological dimension of the polygon: 2
+func (p PolygonZM) Dimension() int {
+	return 2
+}
+
+//CoordinateDimension returns the number of values of each coordinate
+func (p PolygonZM) CoordinateDimension() int {
+	return 4
+}
+
+//IsEmpty returns true if the polygon has no exterior ring
+func (p PolygonZM) IsEmpty() bool {
+	return len(p) == 0 || len(p[0]) == 0
+}
+
+//NumPoints returns the number of points of the polygon
+func (p PolygonZM) NumPoints() int {
+	n := 0
+	for _, ring := range p {
+		n += len(ring)
+	}
+	return n
+}
+
+//NumGeometries returns the number of geometries: 1
+func (p PolygonZM) NumGeometries() int {
+	return 1
+}
+
+//GeometryN returns the polygon itself if n is 0, nil otherwise
+func (p PolygonZM) GeometryN(n int) Geometry {
+	if n != 0 {
+		return nil
+	}
+	return p
+}
+
+//ExteriorRing returns the exterior ring of the polygon, or nil if the polygon has no ring
+func (p PolygonZM) ExteriorRing() Curve {
+	if len(p) == 0 {
+		return nil
+	}
+	return p[0]
+}
+
+//NumInteriorRings returns the number of holes of the polygon
+func (p PolygonZM) NumInteriorRings() int {
+	if len(p) == 0 {
+		return 0
+	}
+	return len(p) - 1
+}
+
+//InteriorRingN returns the n-th (zero based) hole of the polygon, or nil if n is out of range
+func (p PolygonZM) InteriorRingN(n int) Curve {
+	if n < 0 || n+1 >= len(p) {
+		return nil
+	}
+	return p[n+1]
+}
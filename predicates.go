@@ -0,0 +1,177 @@
+// Copyright 2015 Simon HEGE. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package geom
+
+import (
+	"math"
+)
+
+//Robust geometric predicates, after Jonathan Richard Shewchuk, "Adaptive Precision Floating-Point
+//Arithmetic and Fast Robust Geometric Predicates" (1997).
+//
+//The determinants are first evaluated with floating point arithmetic. When the result is smaller
+//than the error bound, they are evaluated again exactly using expansions: sums of non-overlapping
+//floating point numbers, sorted by increasing magnitude.
+
+const epsilon = 1.0 / (1 << 53) //half the machine epsilon
+
+var (
+	orientErrBound   = (3 + 16*epsilon) * epsilon
+	inCircleErrBound = (10 + 96*epsilon) * epsilon
+)
+
+//twoSum returns the sum of a and b, and the rounding error of this sum
+func twoSum(a, b float64) (float64, float64) {
+	x := a + b
+	bv := x - a
+	av := x - bv
+	return x, (a - av) + (b - bv)
+}
+
+//twoProduct returns the product of a and b, and the rounding error of this product
+func twoProduct(a, b float64) (float64, float64) {
+	x := a * b
+	return x, math.FMA(a, b, -x)
+}
+
+//diff returns a - b as an exact expansion
+func diff(a, b float64) []float64 {
+	x, y := twoSum(a, -b)
+	return []float64{y, x}
+}
+
+//growExpansion returns the expansion e + b, without zero components
+func growExpansion(e []float64, b float64) []float64 {
+	h := make([]float64, 0, len(e)+1)
+	q := b
+	for _, v := range e {
+		var r float64
+		q, r = twoSum(q, v)
+		if r != 0 {
+			h = append(h, r)
+		}
+	}
+	if q != 0 || len(h) == 0 {
+		h = append(h, q)
+	}
+	return h
+}
+
+//expansionSum returns the expansion e + f
+func expansionSum(e, f []float64) []float64 {
+	for _, v := range f {
+		e = growExpansion(e, v)
+	}
+	return e
+}
+
+//scaleExpansion returns the expansion e * b
+func scaleExpansion(e []float64, b float64) []float64 {
+	var h []float64
+	for _, v := range e {
+		x, y := twoProduct(v, b)
+		h = growExpansion(growExpansion(h, y), x)
+	}
+	if len(h) == 0 {
+		h = []float64{0}
+	}
+	return h
+}
+
+//productExpansion returns the expansion e * f
+func productExpansion(e, f []float64) []float64 {
+	h := []float64{0}
+	for _, v := range f {
+		h = expansionSum(h, scaleExpansion(e, v))
+	}
+	return h
+}
+
+//negateExpansion returns the expansion -e
+func negateExpansion(e []float64) []float64 {
+	h := make([]float64, len(e))
+	for i, v := range e {
+		h[i] = -v
+	}
+	return h
+}
+
+//estimate returns an approximation of the expansion, with the exact sign
+func estimate(e []float64) float64 {
+	sum := 0.0
+	for _, v := range e {
+		sum += v
+	}
+	if sum == 0 && len(e) > 0 {
+		//The sign is the one of the largest component
+		return e[len(e)-1]
+	}
+	return sum
+}
+
+//Orient2D returns a positive value if the points a, b and c are in counter-clockwise order,
+//a negative value if they are in clockwise order, and zero if they are collinear.
+//The sign of the result is exact; its magnitude approximates twice the signed area of the triangle.
+func Orient2D(a, b, c Point) float64 {
+	detLeft := (a.X - c.X) * (b.Y - c.Y)
+	detRight := (a.Y - c.Y) * (b.X - c.X)
+	det := detLeft - detRight
+
+	detSum := math.Abs(detLeft) + math.Abs(detRight)
+	if math.Abs(det) >= orientErrBound*detSum {
+		return det
+	}
+	return orient2DExact(a, b, c)
+}
+
+func orient2DExact(a, b, c Point) float64 {
+	left := productExpansion(diff(a.X, c.X), diff(b.Y, c.Y))
+	right := productExpansion(diff(a.Y, c.Y), diff(b.X, c.X))
+	return estimate(expansionSum(left, negateExpansion(right)))
+}
+
+//InCircle returns a positive value if d lies inside the circle passing through a, b and c,
+//a negative value if it lies outside, and zero if the four points are cocircular.
+//The points a, b and c must be in counter-clockwise order, otherwise the sign is reversed.
+//The sign of the result is exact.
+func InCircle(a, b, c, d Point) float64 {
+	adx, ady := a.X-d.X, a.Y-d.Y
+	bdx, bdy := b.X-d.X, b.Y-d.Y
+	cdx, cdy := c.X-d.X, c.Y-d.Y
+
+	bdxcdy, cdxbdy := bdx*cdy, cdx*bdy
+	alift := adx*adx + ady*ady
+	cdxady, adxcdy := cdx*ady, adx*cdy
+	blift := bdx*bdx + bdy*bdy
+	adxbdy, bdxady := adx*bdy, bdx*ady
+	clift := cdx*cdx + cdy*cdy
+
+	det := alift*(bdxcdy-cdxbdy) + blift*(cdxady-adxcdy) + clift*(adxbdy-bdxady)
+	permanent := (math.Abs(bdxcdy)+math.Abs(cdxbdy))*alift +
+		(math.Abs(cdxady)+math.Abs(adxcdy))*blift +
+		(math.Abs(adxbdy)+math.Abs(bdxady))*clift
+	if math.Abs(det) > inCircleErrBound*permanent {
+		return det
+	}
+	return inCircleExact(a, b, c, d)
+}
+
+func inCircleExact(a, b, c, d Point) float64 {
+	adx, ady := diff(a.X, d.X), diff(a.Y, d.Y)
+	bdx, bdy := diff(b.X, d.X), diff(b.Y, d.Y)
+	cdx, cdy := diff(c.X, d.X), diff(c.Y, d.Y)
+
+	lift := func(x, y []float64) []float64 {
+		return expansionSum(productExpansion(x, x), productExpansion(y, y))
+	}
+	cross := func(x1, y1, x2, y2 []float64) []float64 {
+		return expansionSum(productExpansion(x1, y2), negateExpansion(productExpansion(x2, y1)))
+	}
+
+	det := productExpansion(lift(adx, ady), cross(bdx, bdy, cdx, cdy))
+	det = expansionSum(det, productExpansion(lift(bdx, bdy), cross(cdx, cdy, adx, ady)))
+	det = expansionSum(det, productExpansion(lift(cdx, cdy), cross(adx, ady, bdx, bdy)))
+	return estimate(det)
+}
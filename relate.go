@@ -0,0 +1,218 @@
+// Copyright 2015 Simon HEGE. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package geom
+
+//IntersectionMatrix is a DE-9IM matrix, describing the intersections between the interior,
+//boundary and exterior of two geometries. It is indexed by the Location in each geometry.
+//Values are the dimension of the intersection: -1 if empty, 0 for points, 1 for lines, 2 for areas.
+type IntersectionMatrix [3][3]int
+
+//String returns the matrix as a 9 characters string, row by row (e.g. "212101212"), using F for empty intersections
+func (m IntersectionMatrix) String() string {
+	b := make([]byte, 0, 9)
+	for i := range m {
+		for j := range m[i] {
+			if m[i][j] < 0 {
+				b = append(b, 'F')
+			} else {
+				b = append(b, byte('0'+m[i][j]))
+			}
+		}
+	}
+	return string(b)
+}
+
+//Matches returns true if the matrix matches the 9 characters pattern.
+//Pattern characters are: T (non-empty), F (empty), * (any), 0, 1 or 2 (dimension).
+func (m IntersectionMatrix) Matches(pattern string) bool {
+	if len(pattern) != 9 {
+		return false
+	}
+	for k := 0; k < 9; k++ {
+		v := m[k/3][k%3]
+		switch pattern[k] {
+		case '*':
+		case 'T', 't':
+			if v < 0 {
+				return false
+			}
+		case 'F', 'f':
+			if v >= 0 {
+				return false
+			}
+		case '0', '1', '2':
+			if v != int(pattern[k]-'0') {
+				return false
+			}
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+//set records an intersection of dimension d, keeping the highest dimension
+func (m *IntersectionMatrix) set(a, b Location, d int) {
+	if d > m[a][b] {
+		m[a][b] = d
+	}
+}
+
+//boundaryDimension returns the dimension of the boundary of the components, or -1 if it is empty
+func (p *parts) boundaryDimension() int {
+	if len(p.polygons) > 0 {
+		return 1
+	}
+	ends := make(map[Point]int)
+	for _, line := range p.lines {
+		ends[line[0]]++
+		ends[line[len(line)-1]]++
+	}
+	for _, n := range ends {
+		if n%2 == 1 {
+			return 0
+		}
+	}
+	return -1
+}
+
+//envelopesDisjoint returns true if the envelopes of a and b do not intersect
+func envelopesDisjoint(a, b *Envelope) bool {
+	return a.Min.X > b.Max.X || a.Max.X < b.Min.X || a.Min.Y > b.Max.Y || a.Max.Y < b.Min.Y
+}
+
+//envelopeContains returns true if b lies within a
+func envelopeContains(a, b *Envelope) bool {
+	return a.Min.X <= b.Min.X && a.Max.X >= b.Max.X && a.Min.Y <= b.Min.Y && a.Max.Y >= b.Max.Y
+}
+
+//Relate returns the DE-9IM intersection matrix of a and b.
+//Z and M values are ignored. Components of geometry collections are merged, polygons taking precedence
+//over lines and lines over points. The boundary of lines follows the mod-2 rule.
+func Relate(a, b Geometry) IntersectionMatrix {
+	var m IntersectionMatrix
+	for i := range m {
+		for j := range m[i] {
+			m[i][j] = -1
+		}
+	}
+	m[Exterior][Exterior] = 2
+
+	pa, pb := newParts(a), newParts(b)
+	if envelopesDisjoint(pa.envelope(), pb.envelope()) {
+		m[Interior][Exterior] = pa.dimension()
+		m[Boundary][Exterior] = pa.boundaryDimension()
+		m[Exterior][Interior] = pb.dimension()
+		m[Exterior][Boundary] = pb.boundaryDimension()
+		return m
+	}
+
+	g := newTopologyGraph(a, b)
+	for _, n := range g.nodes {
+		m.set(n.loc[0], n.loc[1], 0)
+	}
+	for _, e := range g.edges {
+		m.set(e.loc[0], e.loc[1], 1)
+		m.set(e.left[0], e.left[1], 2)
+		m.set(e.right[0], e.right[1], 2)
+	}
+	return m
+}
+
+//Equals returns true if a and b are topologically equal
+func Equals(a, b Geometry) bool {
+	if a.IsEmpty() || b.IsEmpty() {
+		return a.IsEmpty() && b.IsEmpty()
+	}
+	if *a.Envelope() != *b.Envelope() {
+		return false
+	}
+	return Relate(a, b).Matches("T*F**FFF*")
+}
+
+//Disjoint returns true if a and b have no point in common
+func Disjoint(a, b Geometry) bool {
+	return !Intersects(a, b)
+}
+
+//Intersects returns true if a and b have at least one point in common
+func Intersects(a, b Geometry) bool {
+	if envelopesDisjoint(a.Envelope(), b.Envelope()) {
+		return false
+	}
+	return !Relate(a, b).Matches("FF*FF****")
+}
+
+//Touches returns true if a and b have at least one point in common, but their interiors do not intersect
+func Touches(a, b Geometry) bool {
+	if envelopesDisjoint(a.Envelope(), b.Envelope()) {
+		return false
+	}
+	if dimension(a) == 0 && dimension(b) == 0 {
+		return false
+	}
+	m := Relate(a, b)
+	return m.Matches("FT*******") || m.Matches("F**T*****") || m.Matches("F***T****")
+}
+
+//Crosses returns true if a and b have some but not all interior points in common,
+//and the dimension of the intersection is less than the maximum dimension of a and b
+func Crosses(a, b Geometry) bool {
+	if envelopesDisjoint(a.Envelope(), b.Envelope()) {
+		return false
+	}
+	da, db := dimension(a), dimension(b)
+	switch {
+	case da == 1 && db == 1:
+		return Relate(a, b).Matches("0********")
+	case da < db:
+		return Relate(a, b).Matches("T*T******")
+	case da > db:
+		return Relate(a, b).Matches("T*****T**")
+	}
+	return false
+}
+
+//Within returns true if a lies in the interior of b
+func Within(a, b Geometry) bool {
+	return Contains(b, a)
+}
+
+//Contains returns true if b lies in a, and the interiors of a and b have at least one point in common
+func Contains(a, b Geometry) bool {
+	if !envelopeContains(a.Envelope(), b.Envelope()) {
+		return false
+	}
+	return Relate(a, b).Matches("T*****FF*")
+}
+
+//Overlaps returns true if a and b have the same dimension, and each one has some but not all points in common with the other
+func Overlaps(a, b Geometry) bool {
+	if envelopesDisjoint(a.Envelope(), b.Envelope()) {
+		return false
+	}
+	da, db := dimension(a), dimension(b)
+	switch {
+	case da != db:
+		return false
+	case da == 1:
+		return Relate(a, b).Matches("1*T***T**")
+	}
+	return Relate(a, b).Matches("T*T***T**")
+}
+
+//Covers returns true if no point of b lies in the exterior of a
+func Covers(a, b Geometry) bool {
+	if b.IsEmpty() || !envelopeContains(a.Envelope(), b.Envelope()) {
+		return false
+	}
+	m := Relate(a, b)
+	return m.Matches("T*****FF*") || m.Matches("*T****FF*") || m.Matches("***T**FF*") || m.Matches("****T*FF*")
+}
+
+//CoveredBy returns true if no point of a lies in the exterior of b
+func CoveredBy(a, b Geometry) bool {
+	return Covers(b, a)
+}
@@ -0,0 +1,143 @@
+// Copyright 2015 Simon HEGE. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package geom
+
+import "testing"
+
+//box returns the polygon of the rectangle from (x0, y0) to (x1, y1)
+func box(x0, y0, x1, y1 float64) Polygon {
+	return Polygon{ring(x0, y0, x1, y0, x1, y1, x0, y1)}
+}
+
+func TestRelate(t *testing.T) {
+	withHole := Polygon{ring(0, 0, 10, 0, 10, 10, 0, 10), ring(4, 4, 4, 6, 6, 6, 6, 4)}
+	tests := []struct {
+		name string
+		a, b Geometry
+		want string
+	}{
+		{"disjoint polygons", box(0, 0, 2, 2), box(5, 5, 6, 6), "FF2FF1212"},
+		{"equal polygons", box(0, 0, 2, 2), Polygon{ring(2, 2, 0, 2, 0, 0, 2, 0)}, "2FFF1FFF2"},
+		{"overlapping polygons", box(0, 0, 2, 2), box(1, 1, 3, 3), "212101212"},
+		{"contained polygon", box(0, 0, 10, 10), box(2, 2, 4, 4), "212FF1FF2"},
+		{"polygon within", box(2, 2, 4, 4), box(0, 0, 10, 10), "2FF1FF212"},
+		{"polygons touching along an edge", box(0, 0, 2, 2), box(2, 0, 4, 2), "FF2F11212"},
+		{"polygons touching at a corner", box(0, 0, 2, 2), box(2, 2, 4, 4), "FF2F01212"},
+		{"polygons sharing part of an edge", box(0, 0, 2, 2), box(2, 1, 4, 3), "FF2F11212"},
+		{"polygon in a hole", withHole, box(4.5, 4.5, 5.5, 5.5), "FF2FF1212"},
+		{"polygon filling a hole", withHole, box(4, 4, 6, 6), "FF2F112F2"},
+		{"polygon covering a hole", withHole, box(3, 3, 7, 7), "2121F12F2"},
+		{"line crossing polygon", LineString{{X: -1, Y: 1}, {X: 3, Y: 1}}, box(0, 0, 2, 2), "101FF0212"},
+		{"line along polygon edge", LineString{{X: 0, Y: 0}, {X: 2, Y: 0}}, box(0, 0, 2, 2), "F1FF0F212"},
+		{"line inside polygon", LineString{{X: 0.5, Y: 1}, {X: 1.5, Y: 1}}, box(0, 0, 2, 2), "1FF0FF212"},
+		{"crossing lines", LineString{{X: 0, Y: 0}, {X: 2, Y: 2}}, LineString{{X: 0, Y: 2}, {X: 2, Y: 0}}, "0F1FF0102"},
+		{"collinear overlapping lines", LineString{{X: 0, Y: 0}, {X: 4, Y: 0}}, LineString{{X: 2, Y: 0}, {X: 6, Y: 0}}, "1010F0102"},
+		{"lines touching at end points", LineString{{X: 0, Y: 0}, {X: 2, Y: 0}}, LineString{{X: 2, Y: 0}, {X: 2, Y: 2}}, "FF1F00102"},
+		{"closed line", LineString{{X: 0, Y: 0}, {X: 2, Y: 0}, {X: 2, Y: 2}, {X: 0, Y: 0}}, LineString{{X: 0, Y: 0}, {X: -1, Y: 0}}, "F01FFF102"},
+		{"point in polygon", &Point{X: 1, Y: 1}, box(0, 0, 2, 2), "0FFFFF212"},
+		{"point on polygon boundary", &Point{X: 2, Y: 1}, box(0, 0, 2, 2), "F0FFFF212"},
+		{"point at line end", &Point{X: 2, Y: 0}, LineString{{X: 0, Y: 0}, {X: 2, Y: 0}}, "F0FFFF102"},
+		{"equal points", &Point{X: 2, Y: 0}, &Point{X: 2, Y: 0}, "0FFFFFFF2"},
+		{"multipolygon", MultiPolygon{box(0, 0, 1, 1), box(3, 3, 4, 4)}, box(0.5, 0.5, 3.5, 3.5), "212101212"},
+	}
+	for _, tt := range tests {
+		if got := Relate(tt.a, tt.b).String(); got != tt.want {
+			t.Errorf("%s: Relate() = %s, want %s", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestIntersectionMatrixMatches(t *testing.T) {
+	m := Relate(box(0, 0, 2, 2), box(1, 1, 3, 3))
+	tests := []struct {
+		pattern string
+		want    bool
+	}{
+		{"212101212", true},
+		{"T*T***T**", true},
+		{"t*t***t**", true},
+		{"*********", true},
+		{"FF*FF****", false},
+		{"1********", false},
+		{"21210121", false},
+		{"21210121x", false},
+	}
+	for _, tt := range tests {
+		if got := m.Matches(tt.pattern); got != tt.want {
+			t.Errorf("Matches(%q) = %v, want %v", tt.pattern, got, tt.want)
+		}
+	}
+}
+
+func TestPredicates(t *testing.T) {
+	square := box(0, 0, 2, 2)
+	tests := []struct {
+		name                                                 string
+		a, b                                                 Geometry
+		equals, disjoint, touches, crosses, within, overlaps bool
+		contains, covers                                     bool
+	}{
+		{"equal", square, Polygon{ring(2, 2, 0, 2, 0, 0, 2, 0)}, true, false, false, false, true, false, true, true},
+		{"disjoint", square, box(5, 5, 6, 6), false, true, false, false, false, false, false, false},
+		{"touching", square, box(2, 0, 4, 2), false, false, true, false, false, false, false, false},
+		{"overlapping", square, box(1, 1, 3, 3), false, false, false, false, false, true, false, false},
+		{"containing", box(0, 0, 10, 10), square, false, false, false, false, false, false, true, true},
+		{"covering a boundary line", square, LineString{{X: 0, Y: 0}, {X: 2, Y: 0}}, false, false, true, false, false, false, false, true},
+		{"crossing line", LineString{{X: -1, Y: 1}, {X: 3, Y: 1}}, square, false, false, false, true, false, false, false, false},
+		{"crossing lines", LineString{{X: 0, Y: 0}, {X: 2, Y: 2}}, LineString{{X: 0, Y: 2}, {X: 2, Y: 0}}, false, false, false, true, false, false, false, false},
+		{"overlapping lines", LineString{{X: 0, Y: 0}, {X: 4, Y: 0}}, LineString{{X: 2, Y: 0}, {X: 6, Y: 0}}, false, false, false, false, false, true, false, false},
+	}
+	for _, tt := range tests {
+		got := []bool{Equals(tt.a, tt.b), Disjoint(tt.a, tt.b), Touches(tt.a, tt.b), Crosses(tt.a, tt.b), Within(tt.a, tt.b), Overlaps(tt.a, tt.b), Contains(tt.a, tt.b), Covers(tt.a, tt.b)}
+		want := []bool{tt.equals, tt.disjoint, tt.touches, tt.crosses, tt.within, tt.overlaps, tt.contains, tt.covers}
+		names := []string{"Equals", "Disjoint", "Touches", "Crosses", "Within", "Overlaps", "Contains", "Covers"}
+		for i := range got {
+			if got[i] != want[i] {
+				t.Errorf("%s: %s() = %v, want %v", tt.name, names[i], got[i], want[i])
+			}
+		}
+		if Intersects(tt.a, tt.b) == tt.disjoint {
+			t.Errorf("%s: Intersects() = %v", tt.name, !tt.disjoint)
+		}
+		if CoveredBy(tt.b, tt.a) != tt.covers {
+			t.Errorf("%s: CoveredBy() = %v, want %v", tt.name, !tt.covers, tt.covers)
+		}
+	}
+}
+
+func TestNodeSnapper(t *testing.T) {
+	s := newNodeSnapper(&Envelope{Min: Point{X: 0, Y: 0}, Max: Point{X: 100, Y: 100}})
+	a := s.snap(Point{X: 1, Y: 1})
+	tests := []struct {
+		pt, want Point
+	}{
+		{Point{X: 1, Y: 1}, a},
+		{Point{X: 1 + 1e-11, Y: 1 - 1e-11}, a},
+		{Point{X: 1 + 1e-9, Y: 1}, Point{X: 1 + 1e-9, Y: 1}},
+		{Point{X: 50, Y: 50}, Point{X: 50, Y: 50}},
+		{Point{X: 50 - 1e-11, Y: 50}, Point{X: 50, Y: 50}},
+	}
+	for _, tt := range tests {
+		if got := s.snap(tt.pt); got != tt.want {
+			t.Errorf("snap(%v) = %v, want %v", tt.pt, got, tt.want)
+		}
+	}
+}
+
+func TestRelateSnapping(t *testing.T) {
+	//Vertices closer than the snapping tolerance are merged, so that nearly identical polygons are equal
+	a := box(0, 0, 10, 10)
+	b := Polygon{ring(0, 0, 10+1e-13, 0, 10, 10, 0, 10-1e-13)}
+	if got := Relate(a, b).String(); got != "2FFF1FFF2" {
+		t.Errorf("Relate() = %s, want 2FFF1FFF2", got)
+	}
+
+	//Intersection point computed from both pairs of crossing segments
+	c := LineString{{X: 0, Y: 0}, {X: 10, Y: 3}, {X: 0, Y: 6}}
+	d := LineString{{X: 1, Y: 10}, {X: 3, Y: -1}}
+	if got := Relate(c, d).String(); got != "0F1FF0102" {
+		t.Errorf("Relate() = %s, want 0F1FF0102", got)
+	}
+}
@@ -0,0 +1,251 @@
+// Copyright 2015 Simon HEGE. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package geom
+
+import (
+	"math"
+)
+
+//segmentGrid is a uniform grid of segments, used to find the distance from points to a geometry
+type segmentGrid struct {
+	segments [][2]Point
+	origin   Point
+	size     float64
+	nx, ny   int
+	cells    map[[2]int][]int
+	visited  []int //query in which each segment was last visited
+	query    int
+}
+
+func newSegmentGrid(segments [][2]Point) *segmentGrid {
+	e := NewEnvelope()
+	for _, s := range segments {
+		e.ExtendPoint(s[0]).ExtendPoint(s[1])
+	}
+	w, h := e.Max.X-e.Min.X, e.Max.Y-e.Min.Y
+	size := math.Sqrt(w * h / float64(len(segments)+1))
+	if size == 0 {
+		size = math.Max(w, h) / float64(len(segments)+1)
+	}
+	if size == 0 || math.IsNaN(size) || math.IsInf(size, 0) {
+		size = 1
+	}
+	g := &segmentGrid{
+		segments: segments,
+		origin:   e.Min,
+		size:     size,
+		nx:       int(w/size) + 1,
+		ny:       int(h/size) + 1,
+		cells:    make(map[[2]int][]int),
+		visited:  make([]int, len(segments)),
+	}
+	for i, s := range segments {
+		min, max := g.cell(Point{X: math.Min(s[0].X, s[1].X), Y: math.Min(s[0].Y, s[1].Y)}), g.cell(Point{X: math.Max(s[0].X, s[1].X), Y: math.Max(s[0].Y, s[1].Y)})
+		for x := min[0]; x <= max[0]; x++ {
+			for y := min[1]; y <= max[1]; y++ {
+				g.cells[[2]int{x, y}] = append(g.cells[[2]int{x, y}], i)
+			}
+		}
+	}
+	return g
+}
+
+func (g *segmentGrid) cell(pt Point) [2]int {
+	return [2]int{int(math.Floor((pt.X - g.origin.X) / g.size)), int(math.Floor((pt.Y - g.origin.Y) / g.size))}
+}
+
+//distance returns the distance from pt to the closest segment. The search stops as soon as a distance
+//not greater than limit is found, the result being then only an upper bound.
+func (g *segmentGrid) distance(pt Point, limit float64) float64 {
+	g.query++
+	c := g.cell(pt)
+	//Rings of cells around c intersecting the grid
+	first, last := 0, 0
+	for _, d := range []int{-c[0], c[0] - g.nx + 1, -c[1], c[1] - g.ny + 1} {
+		if d > first {
+			first = d
+		}
+	}
+	for _, d := range []int{c[0], g.nx - 1 - c[0], c[1], g.ny - 1 - c[1]} {
+		if d < 0 {
+			d = -d
+		}
+		if d > last {
+			last = d
+		}
+	}
+
+	best := math.Inf(1)
+	visit := func(x, y int) {
+		if x < 0 || x >= g.nx || y < 0 || y >= g.ny {
+			return
+		}
+		for _, i := range g.cells[[2]int{x, y}] {
+			if g.visited[i] == g.query {
+				continue
+			}
+			g.visited[i] = g.query
+			if d := segmentDistance(pt, g.segments[i][0], g.segments[i][1]); d < best {
+				best = d
+			}
+		}
+	}
+	for k := first; k <= last; k++ {
+		//Segments in the next rings are farther than k-1 cells
+		if best <= limit || best <= float64(k-1)*g.size {
+			break
+		}
+		//Cells of the ring, limited to the grid
+		minX, maxX := c[0]-k, c[0]+k
+		if minX < 0 {
+			minX = 0
+		}
+		if maxX > g.nx-1 {
+			maxX = g.nx - 1
+		}
+		for x := minX; x <= maxX; x++ {
+			visit(x, c[1]-k)
+			if k > 0 {
+				visit(x, c[1]+k)
+			}
+		}
+		minY, maxY := c[1]-k+1, c[1]+k-1
+		if minY < 0 {
+			minY = 0
+		}
+		if maxY > g.ny-1 {
+			maxY = g.ny - 1
+		}
+		for y := minY; y <= maxY; y++ {
+			visit(c[0]-k, y)
+			visit(c[0]+k, y)
+		}
+	}
+	return best
+}
+
+//densify returns the points of the segments, with each segment divided in equal parts of at most fraction of its length
+func densify(segments [][2]Point, fraction float64) []Point {
+	n := 1
+	if fraction > 0 && fraction < 1 {
+		n = int(math.Ceil(1 / fraction))
+	}
+	points := make([]Point, 0, len(segments)*n+1)
+	for i, s := range segments {
+		if i == 0 || segments[i-1][1] != s[0] {
+			points = append(points, s[0])
+		}
+		if s[0] == s[1] {
+			continue
+		}
+		for j := 1; j < n; j++ {
+			t := float64(j) / float64(n)
+			points = append(points, Point{X: s[0].X + t*(s[1].X-s[0].X), Y: s[0].Y + t*(s[1].Y-s[0].Y)})
+		}
+		points = append(points, s[1])
+	}
+	return points
+}
+
+//directedHausdorff returns the largest distance from the points to the geometry made of the segments and polygons,
+//starting from the lower bound max
+func directedHausdorff(points []Point, segments [][2]Point, polygons [][][]Point, max float64) float64 {
+	g := newSegmentGrid(segments)
+	for _, pt := range points {
+		d := g.distance(pt, max)
+		if d <= max {
+			continue
+		}
+		for _, polygon := range polygons {
+			if locateInRings(pt, polygon) != Exterior {
+				d = 0
+				break
+			}
+		}
+		max = math.Max(max, d)
+	}
+	return max
+}
+
+//HausdorffDistance returns the discrete Hausdorff distance between a and b: the largest distance
+//from a vertex of one geometry to the other geometry. Points inside a polygon are at a distance of 0.
+//All the geometry types and dimension variants are accepted, Z and M values are ignored.
+//
+//With a densifyFraction between 0 and 1, each segment is divided in equal parts of at most this fraction
+//of its length, and the intermediate points are also used, giving a better approximation of the exact distance.
+//Use 0 to only use the vertices. The distance is infinite if a or b is empty.
+func HausdorffDistance(a, b Geometry, densifyFraction float64) float64 {
+	pa, pb := newParts(a), newParts(b)
+	fa, fb := pa.facets(), pb.facets()
+	if len(fa) == 0 || len(fb) == 0 {
+		return math.Inf(1)
+	}
+	max := directedHausdorff(densify(fa, densifyFraction), fb, pb.polygons, 0)
+	return directedHausdorff(densify(fb, densifyFraction), fa, pa.polygons, max)
+}
+
+//FrechetDistance returns the discrete Fréchet distance between the vertices of a and b, taken in order:
+//the shortest leash allowing to walk along both vertex sequences forward, one vertex at a time on either side.
+//Unlike HausdorffDistance, it takes the direction of lines into account, making it suitable to compare tracks.
+//The components of multi geometries are concatenated, and Z and M values are ignored.
+//
+//The computation takes a time proportional to the product of the number of vertices, and memory proportional
+//to the smallest one. The distance is infinite if a or b is empty.
+func FrechetDistance(a, b Geometry) float64 {
+	p, q := frechetPoints(a), frechetPoints(b)
+	if len(p) == 0 || len(q) == 0 {
+		return math.Inf(1)
+	}
+	if len(q) > len(p) {
+		p, q = q, p
+	}
+
+	//Two rows of the coupling table, on squared distances
+	dist := func(a, b Point) float64 {
+		dx, dy := a.X-b.X, a.Y-b.Y
+		return dx*dx + dy*dy
+	}
+	prev, row := make([]float64, len(q)), make([]float64, len(q))
+	prev[0] = dist(p[0], q[0])
+	for j := 1; j < len(q); j++ {
+		prev[j] = math.Max(dist(p[0], q[j]), prev[j-1])
+	}
+	for i := 1; i < len(p); i++ {
+		pt := p[i]
+		row[0] = math.Max(dist(pt, q[0]), prev[0])
+		for j := 1; j < len(q); j++ {
+			c := prev[j-1]
+			if prev[j] < c {
+				c = prev[j]
+			}
+			if row[j-1] < c {
+				c = row[j-1]
+			}
+			if d := dist(pt, q[j]); d > c {
+				c = d
+			}
+			row[j] = c
+		}
+		prev, row = row, prev
+	}
+	return math.Sqrt(prev[len(q)-1])
+}
+
+//frechetPoints returns the vertices of g, in order, ignoring the NaN points
+func frechetPoints(g Geometry) []Point {
+	var points []Point
+	if g == nil {
+		return points
+	}
+	g.Iterate(func(p []Point) error {
+		for _, pt := range p {
+			if !math.IsNaN(pt.X) && !math.IsNaN(pt.Y) {
+				points = append(points, pt)
+			}
+		}
+		return nil
+	})
+	return points
+}
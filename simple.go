@@ -0,0 +1,70 @@
+// Copyright 2015 Simon HEGE. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package geom
+
+import (
+	"math"
+)
+
+//onSegment returns true if p, collinear with the segment [a, b], lies on it
+func onSegment(a, b, p Point) bool {
+	return p.X >= math.Min(a.X, b.X) && p.X <= math.Max(a.X, b.X) && p.Y >= math.Min(a.Y, b.Y) && p.Y <= math.Max(a.Y, b.Y)
+}
+
+//segmentsIntersect returns true if the segments [a, b] and [c, d] share at least one point
+func segmentsIntersect(a, b, c, d Point) bool {
+	o1, o2 := Orient2D(a, b, c), Orient2D(a, b, d)
+	o3, o4 := Orient2D(c, d, a), Orient2D(c, d, b)
+
+	if ((o1 > 0 && o2 < 0) || (o1 < 0 && o2 > 0)) && ((o3 > 0 && o4 < 0) || (o3 < 0 && o4 > 0)) {
+		return true
+	}
+	return (o1 == 0 && onSegment(a, b, c)) || (o2 == 0 && onSegment(a, b, d)) ||
+		(o3 == 0 && onSegment(c, d, a)) || (o4 == 0 && onSegment(c, d, b))
+}
+
+//isSimple returns true if the line does not pass through the same point twice,
+//except for the first and last points of a closed line.
+//Repeated consecutive points are ignored.
+func isSimple(points []Point) bool {
+	//Remove repeated consecutive points
+	pts := make([]Point, 0, len(points))
+	for _, pt := range points {
+		if len(pts) == 0 || pts[len(pts)-1] != pt {
+			pts = append(pts, pt)
+		}
+	}
+	n := len(pts) - 1 //number of segments
+	closed := n > 0 && pts[0] == pts[n]
+
+	for i := 0; i < n; i++ {
+		for j := i + 1; j < n; j++ {
+			a, b, c, d := pts[i], pts[i+1], pts[j], pts[j+1]
+			adjacent := j == i+1
+			closing := closed && i == 0 && j == n-1
+			if !adjacent && !closing {
+				if segmentsIntersect(a, b, c, d) {
+					return false
+				}
+				continue
+			}
+
+			//Adjacent segments only share their common end point
+			if adjacent && Orient2D(a, b, d) == 0 && onSegment(a, b, d) {
+				return false
+			}
+			if adjacent && Orient2D(c, d, a) == 0 && onSegment(c, d, a) {
+				return false
+			}
+			if closing && Orient2D(c, d, b) == 0 && onSegment(c, d, b) {
+				return false
+			}
+			if closing && Orient2D(a, b, c) == 0 && onSegment(a, b, c) {
+				return false
+			}
+		}
+	}
+	return true
+}
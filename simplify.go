@@ -0,0 +1,472 @@
+// Copyright 2015 Simon HEGE. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package geom
+
+import (
+	"container/heap"
+	"fmt"
+	"math"
+	"reflect"
+)
+
+//SimplifyAlgorithm is a line simplification algorithm
+type SimplifyAlgorithm int
+
+//Simplification algorithms
+const (
+	DouglasPeucker    SimplifyAlgorithm = iota //tolerance is the maximum distance between the simplified and the original lines
+	VisvalingamWhyatt                          //tolerance is the minimum area of the triangle formed by a vertex and its neighbours
+)
+
+//Simplify returns a simplified copy of g, of the same type, removing vertices of lines and polygon rings.
+//Line end points and the first point of rings are always kept. Rings which collapse are removed,
+//as well as polygons whose shell collapses. Points are returned unchanged, and geometry collections
+//are simplified recursively.
+//
+//The retained vertices keep their Z and M values. The result may be invalid: see SimplifyPreserveTopology.
+func Simplify(g Geometry, tolerance float64, algorithm SimplifyAlgorithm) (Geometry, error) {
+	return simplify(g, tolerance, algorithm, false)
+}
+
+//SimplifyPreserveTopology is like Simplify, but a vertex is only removed if it creates no intersection
+//between the lines and rings of g and does not move another part of g to the other side of a line.
+//Rings keep at least 3 distinct vertices, so that polygons remain valid if g is valid.
+//The components of geometry collections are simplified independently.
+func SimplifyPreserveTopology(g Geometry, tolerance float64, algorithm SimplifyAlgorithm) (Geometry, error) {
+	return simplify(g, tolerance, algorithm, true)
+}
+
+func simplify(g Geometry, tolerance float64, algorithm SimplifyAlgorithm, preserveTopology bool) (Geometry, error) {
+	//Pointers to slice based geometries, as returned by Clone
+	if v := reflect.ValueOf(g); v.Kind() == reflect.Ptr && !v.IsNil() && v.Elem().Kind() == reflect.Slice {
+		g = v.Elem().Interface().(Geometry)
+	}
+	if g == nil {
+		return nil, fmt.Errorf("Unsupported geometry type: %v", reflect.TypeOf(g))
+	}
+
+	polygonLevel := -1
+	switch g.GeometryType() {
+	case "Point", "MultiPoint":
+		return cloneGeometry(g), nil
+	case "GeometryCollection":
+		v := reflect.ValueOf(g)
+		c := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			s, err := simplify(v.Index(i).Interface().(Geometry), tolerance, algorithm, preserveTopology)
+			if err != nil {
+				return nil, err
+			}
+			c.Index(i).Set(reflect.ValueOf(s))
+		}
+		return c.Interface().(Geometry), nil
+	case "LineString", "MultiLineString":
+	case "Polygon":
+		polygonLevel = 0
+	case "MultiPolygon":
+		polygonLevel = 1
+	default:
+		return nil, fmt.Errorf("Unsupported geometry type: %v", reflect.TypeOf(g))
+	}
+
+	var parts [][]Point
+	g.Iterate(func(points []Point) error {
+		parts = append(parts, points)
+		return nil
+	})
+
+	var kept [][]int
+	if algorithm == DouglasPeucker && !preserveTopology {
+		kept = make([][]int, len(parts))
+		for i, points := range parts {
+			kept[i] = douglasPeucker(points, tolerance)
+			if polygonLevel >= 0 && len(kept[i]) < 4 {
+				kept[i] = nil
+			}
+		}
+	} else {
+		kept = newSimplifier(parts, polygonLevel >= 0, preserveTopology).run(tolerance, algorithm)
+	}
+
+	next := 0
+	v := reflect.ValueOf(g)
+	r := selectPoints(v, polygonLevel, kept, &next)
+	if !r.IsValid() {
+		r = reflect.MakeSlice(v.Type(), 0, 0)
+	}
+	return r.Interface().(Geometry), nil
+}
+
+//selectPoints returns a copy of the slice based geometry v, keeping for each part (in the Iterate order)
+//the points whose indices are given. Parts without indices are removed; if polygonLevel is 0, v is a polygon
+//and the result is invalid if its shell is removed.
+func selectPoints(v reflect.Value, polygonLevel int, kept [][]int, next *int) reflect.Value {
+	if v.Type().Elem().Kind() == reflect.Struct {
+		indices := kept[*next]
+		*next++
+		if indices == nil {
+			return reflect.Value{}
+		}
+		r := reflect.MakeSlice(v.Type(), len(indices), len(indices))
+		for i, j := range indices {
+			r.Index(i).Set(v.Index(j))
+		}
+		return r
+	}
+
+	r := reflect.MakeSlice(v.Type(), 0, v.Len())
+	for i := 0; i < v.Len(); i++ {
+		c := selectPoints(v.Index(i), polygonLevel-1, kept, next)
+		if c.IsValid() {
+			r = reflect.Append(r, c)
+			continue
+		}
+		if polygonLevel == 0 && i == 0 {
+			//Collapsed shell: the holes are ignored
+			*next += v.Len() - 1
+			return reflect.Value{}
+		}
+	}
+	return r
+}
+
+//douglasPeucker returns the indices of the points kept by the Douglas-Peucker algorithm
+func douglasPeucker(points []Point, tolerance float64) []int {
+	if len(points) < 3 {
+		indices := make([]int, len(points))
+		for i := range indices {
+			indices[i] = i
+		}
+		return indices
+	}
+
+	keep := make([]bool, len(points))
+	keep[0], keep[len(points)-1] = true, true
+	stack := [][2]int{{0, len(points) - 1}}
+	for len(stack) > 0 {
+		section := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		first, last := section[0], section[1]
+
+		farthest, maxDistance := -1, -1.0
+		for i := first + 1; i < last; i++ {
+			if d := segmentDistance(points[i], points[first], points[last]); d > maxDistance {
+				farthest, maxDistance = i, d
+			}
+		}
+		if farthest >= 0 && maxDistance > tolerance {
+			keep[farthest] = true
+			stack = append(stack, [2]int{first, farthest}, [2]int{farthest, last})
+		}
+	}
+
+	var indices []int
+	for i, k := range keep {
+		if k {
+			indices = append(indices, i)
+		}
+	}
+	return indices
+}
+
+//simplifyCandidate is a vertex which may be removed, in a priority queue
+type simplifyCandidate struct {
+	part, index int
+	priority    float64
+	version     int
+}
+
+type simplifyQueue []simplifyCandidate
+
+func (q simplifyQueue) Len() int            { return len(q) }
+func (q simplifyQueue) Less(i, j int) bool  { return q[i].priority < q[j].priority }
+func (q simplifyQueue) Swap(i, j int)       { q[i], q[j] = q[j], q[i] }
+func (q *simplifyQueue) Push(x interface{}) { *q = append(*q, x.(simplifyCandidate)) }
+func (q *simplifyQueue) Pop() interface{} {
+	old := *q
+	c := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return c
+}
+
+//simplifier removes vertices one by one from a set of lines or rings,
+//optionally checking that each removal preserves the topology
+type simplifier struct {
+	parts            [][]Point //rings are stored without their closing point
+	rings            bool
+	closed           []bool //the ring had a closing point
+	prev, next       [][]int
+	alive            [][]bool
+	count            []int //number of alive vertices of each part
+	version          [][]int
+	area             [][]float64 //Visvalingam-Whyatt effective areas
+	preserveTopology bool
+	grid             *simplifyGrid
+}
+
+func newSimplifier(parts [][]Point, rings, preserveTopology bool) *simplifier {
+	s := &simplifier{rings: rings, preserveTopology: preserveTopology}
+	for _, points := range parts {
+		closed := rings && len(points) > 1 && points[0] == points[len(points)-1]
+		if closed {
+			points = points[:len(points)-1]
+		}
+		n := len(points)
+		prev, next, alive := make([]int, n), make([]int, n), make([]bool, n)
+		for i := range points {
+			prev[i], next[i], alive[i] = i-1, i+1, true
+		}
+		if rings && n > 0 {
+			prev[0], next[n-1] = n-1, 0
+		}
+		s.parts = append(s.parts, points)
+		s.closed = append(s.closed, closed)
+		s.prev = append(s.prev, prev)
+		s.next = append(s.next, next)
+		s.alive = append(s.alive, alive)
+		s.count = append(s.count, n)
+		s.version = append(s.version, make([]int, n))
+		s.area = append(s.area, make([]float64, n))
+	}
+	if preserveTopology {
+		s.grid = newSimplifyGrid(s)
+	}
+	return s
+}
+
+//removable returns true if the vertex is not an end point and the part has enough vertices
+func (s *simplifier) removable(part, i int) bool {
+	if i == 0 {
+		return false
+	}
+	if !s.rings {
+		return i != len(s.parts[part])-1
+	}
+	if s.preserveTopology {
+		return s.count[part] > 3
+	}
+	return s.count[part] > 2
+}
+
+//triangleArea returns the area of the triangle formed by the vertex and its neighbours
+func (s *simplifier) triangleArea(part, i int) float64 {
+	points := s.parts[part]
+	return math.Abs(Orient2D(points[s.prev[part][i]], points[i], points[s.next[part][i]])) / 2
+}
+
+//run removes the vertices and returns the indices of the remaining ones, nil for collapsed rings
+func (s *simplifier) run(tolerance float64, algorithm SimplifyAlgorithm) [][]int {
+	var queue simplifyQueue
+	limit := tolerance
+	if algorithm == DouglasPeucker {
+		//Candidates are the vertices removed by the Douglas-Peucker algorithm
+		limit = 1
+		for part, points := range s.parts {
+			closed := points
+			if s.rings && len(points) > 0 {
+				closed = append(points[:len(points):len(points)], points[0])
+			}
+			keep := make([]bool, len(closed))
+			for _, i := range douglasPeucker(closed, tolerance) {
+				keep[i] = true
+			}
+			for i := range points {
+				if !keep[i] && s.removable(part, i) {
+					queue = append(queue, simplifyCandidate{part: part, index: i})
+				}
+			}
+		}
+	} else {
+		for part, points := range s.parts {
+			for i := range points {
+				if s.removable(part, i) {
+					s.area[part][i] = s.triangleArea(part, i)
+					queue = append(queue, simplifyCandidate{part: part, index: i, priority: s.area[part][i]})
+				}
+			}
+		}
+	}
+	heap.Init(&queue)
+
+	for queue.Len() > 0 {
+		c := heap.Pop(&queue).(simplifyCandidate)
+		part, i := c.part, c.index
+		if c.priority >= limit {
+			break
+		}
+		if !s.alive[part][i] || c.version != s.version[part][i] || !s.removable(part, i) {
+			continue
+		}
+		if s.preserveTopology && !s.grid.canRemove(part, i) {
+			continue
+		}
+
+		p, n := s.prev[part][i], s.next[part][i]
+		s.alive[part][i] = false
+		s.count[part]--
+		s.next[part][p], s.prev[part][n] = n, p
+		if s.grid != nil {
+			s.grid.addSegment(part, p, n)
+		}
+
+		//The effective area of the neighbours changes. With Douglas-Peucker, the candidates are fixed:
+		//the vertices kept by the algorithm must not be removed.
+		if algorithm != VisvalingamWhyatt {
+			continue
+		}
+		for _, j := range []int{p, n} {
+			if !s.removable(part, j) {
+				continue
+			}
+			s.version[part][j]++
+			s.area[part][j] = math.Max(s.triangleArea(part, j), s.area[part][i])
+			heap.Push(&queue, simplifyCandidate{part: part, index: j, priority: s.area[part][j], version: s.version[part][j]})
+		}
+	}
+
+	kept := make([][]int, len(s.parts))
+	for part, points := range s.parts {
+		var indices []int
+		for i := range points {
+			if s.alive[part][i] {
+				indices = append(indices, i)
+			}
+		}
+		if s.rings {
+			if len(indices) < 3 {
+				continue
+			}
+			if s.closed[part] {
+				indices = append(indices, len(points))
+			}
+		}
+		kept[part] = indices
+	}
+	return kept
+}
+
+//simplifyGrid is a spatial index of the vertices and segments of a simplifier
+type simplifyGrid struct {
+	s        *simplifier
+	origin   Point
+	size     float64
+	vertices map[[2]int][][2]int //part and index of the vertices in each cell
+	segments map[[2]int][][3]int //part and indices of the segments crossing each cell
+}
+
+func newSimplifyGrid(s *simplifier) *simplifyGrid {
+	e := NewEnvelope()
+	n := 0
+	for _, points := range s.parts {
+		for _, pt := range points {
+			e.ExtendPoint(pt)
+		}
+		n += len(points)
+	}
+	size := math.Max(e.Max.X-e.Min.X, e.Max.Y-e.Min.Y) / math.Sqrt(float64(n+1))
+	if size == 0 || math.IsNaN(size) || math.IsInf(size, 0) {
+		size = 1
+	}
+	g := &simplifyGrid{
+		s:        s,
+		origin:   e.Min,
+		size:     size,
+		vertices: make(map[[2]int][][2]int),
+		segments: make(map[[2]int][][3]int),
+	}
+	for part, points := range s.parts {
+		for i, pt := range points {
+			c := g.cell(pt)
+			g.vertices[c] = append(g.vertices[c], [2]int{part, i})
+			if j := s.next[part][i]; j < len(points) {
+				g.addSegment(part, i, j)
+			}
+		}
+	}
+	return g
+}
+
+func (g *simplifyGrid) cell(pt Point) [2]int {
+	return [2]int{int(math.Floor((pt.X - g.origin.X) / g.size)), int(math.Floor((pt.Y - g.origin.Y) / g.size))}
+}
+
+//cells calls f for each cell intersecting the envelope of the points
+func (g *simplifyGrid) cells(f func(c [2]int), points ...Point) {
+	e := NewEnvelope()
+	for _, pt := range points {
+		e.ExtendPoint(pt)
+	}
+	min, max := g.cell(e.Min), g.cell(e.Max)
+	for x := min[0]; x <= max[0]; x++ {
+		for y := min[1]; y <= max[1]; y++ {
+			f([2]int{x, y})
+		}
+	}
+}
+
+func (g *simplifyGrid) addSegment(part, i, j int) {
+	points := g.s.parts[part]
+	g.cells(func(c [2]int) {
+		g.segments[c] = append(g.segments[c], [3]int{part, i, j})
+	}, points[i], points[j])
+}
+
+//canRemove returns true if removing the vertex creates no intersection, and no other vertex lies in the triangle
+//formed by the vertex and its neighbours
+func (g *simplifyGrid) canRemove(part, i int) bool {
+	s := g.s
+	points := s.parts[part]
+	p, n := s.prev[part][i], s.next[part][i]
+	a, v, b := points[p], points[i], points[n]
+
+	ok := true
+	g.cells(func(c [2]int) {
+		if !ok {
+			return
+		}
+		for _, seg := range g.segments[c] {
+			sp, si, sj := seg[0], seg[1], seg[2]
+			if !s.alive[sp][si] || !s.alive[sp][sj] || s.next[sp][si] != sj {
+				continue
+			}
+			if sp == part && (si == i || sj == i) {
+				continue
+			}
+			q, r := s.parts[sp][si], s.parts[sp][sj]
+			for _, pt := range segmentIntersection(a, b, q, r) {
+				//Only touching at a common end point is allowed
+				if !((pt == a || pt == b) && (pt == q || pt == r)) {
+					ok = false
+				}
+			}
+		}
+	}, a, b)
+	if !ok {
+		return false
+	}
+
+	e := NewEnvelope().ExtendPoint(a).ExtendPoint(v).ExtendPoint(b)
+	g.cells(func(c [2]int) {
+		if !ok {
+			return
+		}
+		for _, vertex := range g.vertices[c] {
+			vp, vi := vertex[0], vertex[1]
+			if !s.alive[vp][vi] || (vp == part && (vi == p || vi == i || vi == n)) {
+				continue
+			}
+			w := s.parts[vp][vi]
+			if w == a || w == b || w.X < e.Min.X || w.X > e.Max.X || w.Y < e.Min.Y || w.Y > e.Max.Y {
+				continue
+			}
+			o1, o2, o3 := Orient2D(a, v, w), Orient2D(v, b, w), Orient2D(b, a, w)
+			if (o1 >= 0 && o2 >= 0 && o3 >= 0) || (o1 <= 0 && o2 <= 0 && o3 <= 0) {
+				ok = false
+				return
+			}
+		}
+	}, a, v, b)
+	return ok
+}
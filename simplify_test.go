@@ -0,0 +1,100 @@
+// Copyright 2015 Simon HEGE. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package geom
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSimplify(t *testing.T) {
+	noisy := LineString{{X: 0, Y: 0}, {X: 1, Y: 0.1}, {X: 2, Y: 0}, {X: 3, Y: 0.1}, {X: 4, Y: 0}}
+	var spike LineString
+	for i := 0; i <= 20; i++ {
+		pt := Point{X: float64(i)}
+		if i == 10 {
+			pt.Y = 5
+		}
+		spike = append(spike, pt)
+	}
+	tests := []struct {
+		name      string
+		g         Geometry
+		tolerance float64
+		algorithm SimplifyAlgorithm
+		want      Geometry
+	}{
+		{"DP noise removed", noisy, 0.5, DouglasPeucker, LineString{{X: 0, Y: 0}, {X: 4, Y: 0}}},
+		{"DP within tolerance", noisy, 0.05, DouglasPeucker, noisy},
+		{"DP spike", spike, 0.1, DouglasPeucker, LineString{{X: 0, Y: 0}, {X: 9, Y: 0}, {X: 10, Y: 5}, {X: 11, Y: 0}, {X: 20, Y: 0}}},
+		{"VW noise removed", noisy, 0.5, VisvalingamWhyatt, LineString{{X: 0, Y: 0}, {X: 4, Y: 0}}},
+		{"VW within tolerance", noisy, 0.01, VisvalingamWhyatt, noisy},
+		{"VW spike", spike, 1, VisvalingamWhyatt, LineString{{X: 0, Y: 0}, {X: 9, Y: 0}, {X: 10, Y: 5}, {X: 11, Y: 0}, {X: 20, Y: 0}}},
+		{"Z kept", LineStringZ{{Point: Point{X: 0, Y: 0}, Z: 1}, {Point: Point{X: 1, Y: 0.1}, Z: 2}, {Point: Point{X: 2, Y: 0}, Z: 3}}, 0.5, DouglasPeucker,
+			LineStringZ{{Point: Point{X: 0, Y: 0}, Z: 1}, {Point: Point{X: 2, Y: 0}, Z: 3}}},
+		{"ring", Polygon{ring(0, 0, 5, 0.1, 10, 0, 10, 10, 0, 10)}, 0.5, DouglasPeucker, box(0, 0, 10, 10)},
+		{"collapsed hole removed", Polygon{ring(0, 0, 10, 0, 10, 10, 0, 10), ring(5, 5, 5, 5.1, 5.1, 5.1)}, 1, DouglasPeucker, box(0, 0, 10, 10)},
+		{"collapsed shell removed", MultiPolygon{box(0, 0, 10, 10), box(20, 20, 20.1, 20.1)}, 1, VisvalingamWhyatt, MultiPolygon{box(0, 0, 10, 10)}},
+		{"point", &Point{X: 1, Y: 2}, 1, DouglasPeucker, &Point{X: 1, Y: 2}},
+		{"multipoint", MultiPoint{{X: 1, Y: 2}, {X: 1, Y: 2}}, 1, DouglasPeucker, MultiPoint{{X: 1, Y: 2}, {X: 1, Y: 2}}},
+		{"pointer", &noisy, 0.5, DouglasPeucker, LineString{{X: 0, Y: 0}, {X: 4, Y: 0}}},
+		{"collection", GeometryCollection{&Point{X: 1, Y: 2}, noisy}, 0.5, DouglasPeucker, GeometryCollection{&Point{X: 1, Y: 2}, LineString{{X: 0, Y: 0}, {X: 4, Y: 0}}}},
+	}
+	for _, tt := range tests {
+		got, err := Simplify(tt.g, tt.tolerance, tt.algorithm)
+		if err != nil || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: Simplify() = %v, %v, want %v", tt.name, got, err, tt.want)
+		}
+	}
+
+	if _, err := Simplify(nil, 1, DouglasPeucker); err == nil {
+		t.Error("Simplify(nil) should return an error")
+	}
+}
+
+func TestSimplifyPreserveTopology(t *testing.T) {
+	//Shell bulging around a hole: removing the top vertex would leave the hole outside
+	bulge := Polygon{ring(0, 0, 10, 0, 10, 10, 5, 11, 0, 10), ring(4.5, 10.2, 5.5, 10.2, 5, 10.6)}
+	//Point of a line inside the bump of another one
+	lines := MultiLineString{{{X: 0, Y: 0}, {X: 5, Y: 1}, {X: 10, Y: 0}}, {{X: 5, Y: 0.5}, {X: 6, Y: 0.5}}}
+	//Small hole, which collapses without topology preservation
+	smallHole := Polygon{ring(0, 0, 10, 0, 10, 10, 0, 10), ring(5, 5, 5, 5.1, 5.1, 5.1)}
+	tests := []struct {
+		name      string
+		g         Geometry
+		tolerance float64
+		algorithm SimplifyAlgorithm
+	}{
+		{"DP bulge", bulge, 1.5, DouglasPeucker},
+		{"VW bulge", bulge, 6, VisvalingamWhyatt},
+		{"DP lines", lines, 2, DouglasPeucker},
+		{"VW lines", lines, 10, VisvalingamWhyatt},
+		{"DP small hole", smallHole, 1, DouglasPeucker},
+		{"VW small hole", smallHole, 1, VisvalingamWhyatt},
+	}
+	for _, tt := range tests {
+		plain, err := Simplify(tt.g, tt.tolerance, tt.algorithm)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if reflect.DeepEqual(plain, tt.g) {
+			t.Errorf("%s: Simplify() should change the geometry", tt.name)
+		}
+		got, err := SimplifyPreserveTopology(tt.g, tt.tolerance, tt.algorithm)
+		if err != nil || !reflect.DeepEqual(got, tt.g) {
+			t.Errorf("%s: SimplifyPreserveTopology() = %v, %v, want %v", tt.name, got, err, tt.g)
+		}
+	}
+
+	//Vertices not involved in a conflict are still removed
+	noisyBulge := Polygon{ring(0, 0, 5, 0.1, 10, 0, 10, 10, 5, 11, 0, 10), bulge[1]}
+	got, err := SimplifyPreserveTopology(noisyBulge, 1.5, DouglasPeucker)
+	if err != nil || !reflect.DeepEqual(got, bulge) {
+		t.Errorf("SimplifyPreserveTopology() = %v, %v, want %v", got, err, bulge)
+	}
+	if !IsValid(got) {
+		t.Errorf("SimplifyPreserveTopology() result is invalid: %v", Validate(got))
+	}
+}
@@ -0,0 +1,572 @@
+// Copyright 2015 Simon HEGE. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package geom
+
+import (
+	"math"
+	"sort"
+)
+
+//parts holds the two-dimensional components of a geometry
+type parts struct {
+	points   []Point
+	lines    [][]Point
+	polygons [][][]Point //Each polygon is a list of rings, the first one being the shell
+}
+
+//removeRepeated returns the points without consecutive duplicates
+func removeRepeated(points []Point) []Point {
+	r := make([]Point, 0, len(points))
+	for _, pt := range points {
+		if len(r) == 0 || r[len(r)-1] != pt {
+			r = append(r, pt)
+		}
+	}
+	return r
+}
+
+//newParts extracts the components of g. Geometry collections are handled recursively.
+func newParts(g Geometry) *parts {
+	p := &parts{}
+	if g == nil {
+		return p
+	}
+	components(g, func(c Geometry) {
+		if c.IsEmpty() {
+			return
+		}
+		switch c.Dimension() {
+		case 0:
+			c.Iterate(func(points []Point) error {
+				for _, pt := range points {
+					if !math.IsNaN(pt.X) && !math.IsNaN(pt.Y) {
+						p.points = append(p.points, pt)
+					}
+				}
+				return nil
+			})
+		case 1:
+			c.Iterate(func(points []Point) error {
+				if line := removeRepeated(points); len(line) > 0 {
+					p.lines = append(p.lines, line)
+				}
+				return nil
+			})
+		case 2:
+			var rings [][]Point
+			c.Iterate(func(ring []Point) error {
+				if ring := removeRepeated(ring); len(ring) > 0 {
+					if ring[0] != ring[len(ring)-1] {
+						ring = append(ring, ring[0])
+					}
+					rings = append(rings, ring)
+				}
+				return nil
+			})
+			if len(rings) > 0 {
+				p.polygons = append(p.polygons, rings)
+			}
+		}
+	})
+	return p
+}
+
+//snap moves the vertices closer than the tolerance of the snapper to the same location.
+//Collapsed rings are removed.
+func (p *parts) snap(s *nodeSnapper) {
+	snapPoints := func(points []Point) []Point {
+		for i := range points {
+			points[i] = s.snap(points[i])
+		}
+		return removeRepeated(points)
+	}
+	for i := range p.points {
+		p.points[i] = s.snap(p.points[i])
+	}
+	for i := range p.lines {
+		p.lines[i] = snapPoints(p.lines[i])
+	}
+	polygons := p.polygons[:0]
+	for _, polygon := range p.polygons {
+		rings := polygon[:0]
+		for _, ring := range polygon {
+			if ring = snapPoints(ring); len(ring) >= 4 {
+				rings = append(rings, ring)
+			} else if len(rings) == 0 {
+				//Collapsed shell
+				break
+			}
+		}
+		if len(rings) > 0 {
+			polygons = append(polygons, rings)
+		}
+	}
+	p.polygons = polygons
+}
+
+//isEmpty returns true if there is no component
+func (p *parts) isEmpty() bool {
+	return len(p.points) == 0 && len(p.lines) == 0 && len(p.polygons) == 0
+}
+
+//dimension returns the highest dimension of the components, or -1 if there is none
+func (p *parts) dimension() int {
+	switch {
+	case len(p.polygons) > 0:
+		return 2
+	case len(p.lines) > 0:
+		return 1
+	case len(p.points) > 0:
+		return 0
+	}
+	return -1
+}
+
+//envelope returns the envelope of the components
+func (p *parts) envelope() *Envelope {
+	e := NewEnvelope()
+	for _, pt := range p.points {
+		e.ExtendPoint(pt)
+	}
+	for _, line := range p.lines {
+		for _, pt := range line {
+			e.ExtendPoint(pt)
+		}
+	}
+	for _, polygon := range p.polygons {
+		for _, pt := range polygon[0] {
+			e.ExtendPoint(pt)
+		}
+	}
+	return e
+}
+
+//locateInRings returns the location of pt relative to a polygon given as a shell followed by holes
+func locateInRings(pt Point, rings [][]Point) Location {
+	loc := locateInRing(pt, rings[0])
+	if loc != Interior {
+		return loc
+	}
+	for _, hole := range rings[1:] {
+		switch locateInRing(pt, hole) {
+		case Boundary:
+			return Boundary
+		case Interior:
+			return Exterior
+		}
+	}
+	return Interior
+}
+
+//locateArea returns the location of pt relative to the polygonal components
+func (p *parts) locateArea(pt Point) Location {
+	loc := Exterior
+	for _, polygon := range p.polygons {
+		switch locateInRings(pt, polygon) {
+		case Interior:
+			return Interior
+		case Boundary:
+			loc = Boundary
+		}
+	}
+	return loc
+}
+
+//locate returns the location of pt relative to all the components.
+//Polygons take precedence over lines, and lines over points.
+//The boundary of lines is made of the end points shared by an odd number of lines (mod-2 rule).
+func (p *parts) locate(pt Point) Location {
+	if loc := p.locateArea(pt); loc != Exterior {
+		return loc
+	}
+
+	onLine := false
+	ends := 0
+	for _, line := range p.lines {
+		if line[0] == pt {
+			ends++
+		}
+		if line[len(line)-1] == pt {
+			ends++
+		}
+		for i := 1; i < len(line) && !onLine; i++ {
+			if Orient2D(line[i-1], line[i], pt) == 0 && onSegment(line[i-1], line[i], pt) {
+				onLine = true
+			}
+		}
+	}
+	if ends%2 == 1 {
+		return Boundary
+	}
+	if onLine {
+		return Interior
+	}
+	for _, q := range p.points {
+		if q == pt {
+			return Interior
+		}
+	}
+	return Exterior
+}
+
+//segment is an input segment of the topology graph
+type segment struct {
+	a, b  Point
+	geom  int  //index of the source geometry: 0 or 1
+	ring  bool //true for polygon rings, false for lines
+	left  bool //for rings, true if the polygon interior is on the left side
+	right bool //for rings, true if the polygon interior is on the right side
+	nodes []Point
+}
+
+func (s *segment) envelope() (minX, minY, maxX, maxY float64) {
+	return math.Min(s.a.X, s.b.X), math.Min(s.a.Y, s.b.Y), math.Max(s.a.X, s.b.X), math.Max(s.a.Y, s.b.Y)
+}
+
+//edgeLabel describes the relationship between an edge and one of the geometries
+type edgeLabel struct {
+	ring    bool //the edge lies on a polygon ring
+	line    bool //the edge lies on a line
+	forward bool //for lines, the line runs from a to b
+	left    bool //the polygon interior is on the left side of the edge
+	right   bool //the polygon interior is on the right side of the edge
+}
+
+//edge is a noded edge of the topology graph. Edges only intersect at their end points.
+type edge struct {
+	a, b   Point //a < b in lexicographic order
+	labels [2]edgeLabel
+
+	//locations relative to each geometry, computed by topologyGraph.label
+	loc         [2]Location
+	left, right [2]Location
+}
+
+//mid returns the middle of the edge
+func (e *edge) mid() Point {
+	return Point{X: (e.a.X + e.b.X) / 2, Y: (e.a.Y + e.b.Y) / 2}
+}
+
+//node is a node of the topology graph
+type node struct {
+	pt    Point
+	ring  [2]bool //the node lies on a polygon ring
+	line  [2]bool //the node lies on a line
+	ends  [2]int  //number of line end points at the node
+	point [2]bool //the node is a point component
+	loc   [2]Location
+}
+
+//topologyGraph is the planar graph made of the noded linework of two geometries,
+//labelled with the locations relative to both geometries
+type topologyGraph struct {
+	parts    [2]*parts
+	segments []*segment
+	nodes    map[Point]*node
+	edges    []*edge
+}
+
+//less compares points in lexicographic order
+func less(p, q Point) bool {
+	return p.X < q.X || (p.X == q.X && p.Y < q.Y)
+}
+
+//newTopologyGraph computes the topology graph of a and b
+func newTopologyGraph(a, b Geometry) *topologyGraph {
+	g := &topologyGraph{
+		parts: [2]*parts{newParts(a), newParts(b)},
+		nodes: make(map[Point]*node),
+	}
+	snapper := newNodeSnapper(g.parts[0].envelope().Extend(g.parts[1].envelope()))
+	g.parts[0].snap(snapper)
+	g.parts[1].snap(snapper)
+
+	//Input segments
+	var segments []*segment
+	for i, p := range g.parts {
+		for _, line := range p.lines {
+			for j := 1; j < len(line); j++ {
+				segments = append(segments, &segment{a: line[j-1], b: line[j], geom: i})
+			}
+		}
+		for _, polygon := range p.polygons {
+			for r, ring := range polygon {
+				area := ringArea(ring)
+				//The interior of the shell and the exterior of holes are on the left of counter-clockwise rings
+				left, right := (area > 0) == (r == 0), (area < 0) == (r == 0)
+				if area == 0 {
+					left, right = false, false
+				}
+				for j := 1; j < len(ring); j++ {
+					segments = append(segments, &segment{a: ring[j-1], b: ring[j], geom: i, ring: true, left: left, right: right})
+				}
+			}
+		}
+	}
+
+	nodeSegments(segments, snapper)
+	g.segments = segments
+
+	//Noded edges, merged when shared
+	edges := make(map[[2]Point]*edge)
+	for _, s := range segments {
+		for i := 1; i < len(s.nodes); i++ {
+			p, q := s.nodes[i-1], s.nodes[i]
+			if p == q {
+				continue
+			}
+			reversed := less(q, p)
+			if reversed {
+				p, q = q, p
+			}
+			e, ok := edges[[2]Point{p, q}]
+			if !ok {
+				e = &edge{a: p, b: q}
+				edges[[2]Point{p, q}] = e
+				g.edges = append(g.edges, e)
+			}
+			l := &e.labels[s.geom]
+			if s.ring {
+				l.ring = true
+				if reversed {
+					l.left, l.right = l.left || s.right, l.right || s.left
+				} else {
+					l.left, l.right = l.left || s.left, l.right || s.right
+				}
+			} else {
+				l.line = true
+				l.forward = l.forward || !reversed
+			}
+			for _, n := range []*node{g.addNode(p), g.addNode(q)} {
+				if s.ring {
+					n.ring[s.geom] = true
+				} else {
+					n.line[s.geom] = true
+				}
+			}
+		}
+	}
+
+	//Line end points and point components
+	for i, p := range g.parts {
+		for _, line := range p.lines {
+			g.addNode(line[0]).ends[i]++
+			g.addNode(line[len(line)-1]).ends[i]++
+			if len(line) == 1 {
+				g.nodes[line[0]].line[i] = true
+			}
+		}
+		for _, pt := range p.points {
+			g.addNode(pt).point[i] = true
+		}
+	}
+
+	g.label()
+	return g
+}
+
+func (g *topologyGraph) addNode(pt Point) *node {
+	n, ok := g.nodes[pt]
+	if !ok {
+		n = &node{pt: pt}
+		g.nodes[pt] = n
+	}
+	return n
+}
+
+//label computes the locations of nodes and edges relative to both geometries
+func (g *topologyGraph) label() {
+	for _, n := range g.nodes {
+		for i, p := range g.parts {
+			switch {
+			case n.ring[i]:
+				n.loc[i] = Boundary
+			case n.line[i] || n.ends[i] > 0:
+				if p.locateArea(n.pt) == Interior {
+					n.loc[i] = Interior
+				} else if n.ends[i]%2 == 1 {
+					n.loc[i] = Boundary
+				} else {
+					n.loc[i] = Interior
+				}
+			default:
+				n.loc[i] = p.locate(n.pt)
+			}
+		}
+	}
+
+	for _, e := range g.edges {
+		for i, p := range g.parts {
+			l := e.labels[i]
+			if l.ring {
+				e.loc[i] = Boundary
+				e.left[i], e.right[i] = Exterior, Exterior
+				if l.left {
+					e.left[i] = Interior
+				}
+				if l.right {
+					e.right[i] = Interior
+				}
+				continue
+			}
+
+			area := p.locateArea(e.mid())
+			if area == Boundary {
+				//Only possible through rounding errors, as the edge would otherwise be noded with the ring
+				area = Interior
+			}
+			e.left[i], e.right[i] = area, area
+			if l.line {
+				e.loc[i] = Interior
+			} else {
+				e.loc[i] = area
+			}
+		}
+	}
+}
+
+//nodeSegments computes the intersections between the segments and stores the sorted nodes of each segment
+func nodeSegments(segments []*segment, snapper *nodeSnapper) {
+	for _, s := range segments {
+		s.nodes = append(s.nodes, s.a, s.b)
+	}
+
+	//Sweep line along the X axis
+	sorted := make([]*segment, len(segments))
+	copy(sorted, segments)
+	sort.Slice(sorted, func(i, j int) bool {
+		return math.Min(sorted[i].a.X, sorted[i].b.X) < math.Min(sorted[j].a.X, sorted[j].b.X)
+	})
+	tolerance := snapper.tolerance
+	for i, s := range sorted {
+		_, minY, maxX, maxY := s.envelope()
+		for _, t := range sorted[i+1:] {
+			tMinX, tMinY, _, tMaxY := t.envelope()
+			if tMinX > maxX+tolerance {
+				break
+			}
+			if tMinY > maxY+tolerance || tMaxY < minY-tolerance {
+				continue
+			}
+			for _, pt := range segmentIntersection(s.a, s.b, t.a, t.b) {
+				pt = snapper.snap(pt)
+				if pt != s.a && pt != s.b {
+					s.nodes = append(s.nodes, pt)
+				}
+				if pt != t.a && pt != t.b {
+					t.nodes = append(t.nodes, pt)
+				}
+			}
+			//Vertices very close to the other segment
+			for _, pt := range []Point{t.a, t.b} {
+				if pt != s.a && pt != s.b && segmentDistance(pt, s.a, s.b) <= tolerance {
+					s.nodes = append(s.nodes, pt)
+				}
+			}
+			for _, pt := range []Point{s.a, s.b} {
+				if pt != t.a && pt != t.b && segmentDistance(pt, t.a, t.b) <= tolerance {
+					t.nodes = append(t.nodes, pt)
+				}
+			}
+		}
+	}
+
+	//Sort the nodes along each segment
+	for _, s := range segments {
+		a, dx, dy := s.a, s.b.X-s.a.X, s.b.Y-s.a.Y
+		nodes := s.nodes
+		sort.SliceStable(nodes, func(i, j int) bool {
+			return (nodes[i].X-a.X)*dx+(nodes[i].Y-a.Y)*dy < (nodes[j].X-a.X)*dx+(nodes[j].Y-a.Y)*dy
+		})
+	}
+}
+
+//segmentIntersection returns the intersection points of the segments [a, b] and [c, d]:
+//none, a single point, or the end points of the overlapping part of collinear segments.
+//Touching end points are returned exactly; proper crossings are computed and thus rounded.
+func segmentIntersection(a, b, c, d Point) []Point {
+	o1, o2 := Orient2D(a, b, c), Orient2D(a, b, d)
+	o3, o4 := Orient2D(c, d, a), Orient2D(c, d, b)
+
+	var points []Point
+	add := func(pt Point) {
+		for _, p := range points {
+			if p == pt {
+				return
+			}
+		}
+		points = append(points, pt)
+	}
+	if o1 == 0 && onSegment(a, b, c) {
+		add(c)
+	}
+	if o2 == 0 && onSegment(a, b, d) {
+		add(d)
+	}
+	if o3 == 0 && onSegment(c, d, a) {
+		add(a)
+	}
+	if o4 == 0 && onSegment(c, d, b) {
+		add(b)
+	}
+	if len(points) > 0 {
+		return points
+	}
+
+	if ((o1 > 0 && o2 < 0) || (o1 < 0 && o2 > 0)) && ((o3 > 0 && o4 < 0) || (o3 < 0 && o4 > 0)) {
+		t := o3 / (o3 - o4)
+		pt := Point{X: a.X + t*(b.X-a.X), Y: a.Y + t*(b.Y-a.Y)}
+		//Keep the rounded point within the envelope of both segments
+		pt.X = math.Max(pt.X, math.Max(math.Min(a.X, b.X), math.Min(c.X, d.X)))
+		pt.X = math.Min(pt.X, math.Min(math.Max(a.X, b.X), math.Max(c.X, d.X)))
+		pt.Y = math.Max(pt.Y, math.Max(math.Min(a.Y, b.Y), math.Min(c.Y, d.Y)))
+		pt.Y = math.Min(pt.Y, math.Min(math.Max(a.Y, b.Y), math.Max(c.Y, d.Y)))
+		return []Point{pt}
+	}
+	return nil
+}
+
+//nodeSnapper merges the vertices and the computed intersection points closer than a tolerance,
+//to avoid creating slivers through rounding errors
+type nodeSnapper struct {
+	tolerance float64
+	cells     map[[2]int64][]Point //nodes, by cell of a grid whose size is the tolerance
+}
+
+//newNodeSnapper returns a snapper whose tolerance is relative to the magnitude of the coordinates in the envelope
+func newNodeSnapper(e *Envelope) *nodeSnapper {
+	magnitude := 1.0
+	for _, v := range []float64{e.Min.X, e.Min.Y, e.Max.X, e.Max.Y} {
+		if !math.IsInf(v, 0) {
+			magnitude = math.Max(magnitude, math.Abs(v))
+		}
+	}
+	return &nodeSnapper{tolerance: 1e-12 * magnitude, cells: make(map[[2]int64][]Point)}
+}
+
+func (s *nodeSnapper) cell(pt Point) [2]int64 {
+	return [2]int64{int64(math.Floor(pt.X / s.tolerance)), int64(math.Floor(pt.Y / s.tolerance))}
+}
+
+func (s *nodeSnapper) add(pt Point) {
+	c := s.cell(pt)
+	s.cells[c] = append(s.cells[c], pt)
+}
+
+//snap returns the node close to pt, or adds pt as a new node
+func (s *nodeSnapper) snap(pt Point) Point {
+	c := s.cell(pt)
+	for i := c[0] - 1; i <= c[0]+1; i++ {
+		for j := c[1] - 1; j <= c[1]+1; j++ {
+			for _, q := range s.cells[[2]int64{i, j}] {
+				if math.Abs(q.X-pt.X) <= s.tolerance && math.Abs(q.Y-pt.Y) <= s.tolerance {
+					return q
+				}
+			}
+		}
+	}
+	s.add(pt)
+	return pt
+}
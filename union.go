@@ -0,0 +1,182 @@
+// Copyright 2015 Simon HEGE. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package geom
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"sync"
+)
+
+//unionNodeCapacity is the number of children of the nodes of the envelope tree
+const unionNodeCapacity = 4
+
+//unionNode is a node of the envelope tree used by UnaryUnion
+type unionNode struct {
+	envelope *Envelope
+	polygons MultiPolygon //for leaves
+	children []*unionNode
+}
+
+func (n *unionNode) center() Point {
+	return Point{X: (n.envelope.Min.X + n.envelope.Max.X) / 2, Y: (n.envelope.Min.Y + n.envelope.Max.Y) / 2}
+}
+
+//packUnionNodes groups the nodes by proximity, using the Sort-Tile-Recursive algorithm,
+//and returns the parent nodes
+func packUnionNodes(nodes []*unionNode) []*unionNode {
+	parentCount := (len(nodes) + unionNodeCapacity - 1) / unionNodeCapacity
+	sliceCount := int(math.Ceil(math.Sqrt(float64(parentCount))))
+	sliceSize := sliceCount * unionNodeCapacity
+
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].center().X < nodes[j].center().X
+	})
+	var parents []*unionNode
+	for start := 0; start < len(nodes); start += sliceSize {
+		end := start + sliceSize
+		if end > len(nodes) {
+			end = len(nodes)
+		}
+		slice := nodes[start:end]
+		sort.Slice(slice, func(i, j int) bool {
+			return slice[i].center().Y < slice[j].center().Y
+		})
+		for i := 0; i < len(slice); i += unionNodeCapacity {
+			j := i + unionNodeCapacity
+			if j > len(slice) {
+				j = len(slice)
+			}
+			parent := &unionNode{envelope: NewEnvelope(), children: slice[i:j]}
+			for _, child := range parent.children {
+				parent.envelope.Extend(child.envelope)
+			}
+			parents = append(parents, parent)
+		}
+	}
+	return parents
+}
+
+//UnaryUnion returns the union of all the polygons in geoms, dissolving their common boundaries.
+//A GeometryCollection can be given directly. Geometries must be Polygon or MultiPolygon (any dimension variant),
+//possibly nested in geometry collections; empty geometries are ignored. Z and M values are dropped.
+//
+//The polygons are grouped by proximity in an envelope tree, and unioned bottom-up so that each overlay
+//only involves neighbouring polygons. When workers is greater than 1, up to workers goroutines
+//are used to union independent branches of the tree.
+func UnaryUnion(geoms []Geometry, workers int) (MultiPolygon, error) {
+	var leaves []*unionNode
+	for _, g := range geoms {
+		if g == nil {
+			return nil, fmt.Errorf("Unsupported geometry type: %v", reflect.TypeOf(g))
+		}
+		var err error
+		components(g, func(c Geometry) {
+			if err != nil || c.IsEmpty() {
+				return
+			}
+			if c.GeometryType() != "Polygon" {
+				err = fmt.Errorf("Unsupported geometry type: %v", reflect.TypeOf(c))
+				return
+			}
+			var p Geometry
+			if p, err = Force2D(c); err == nil {
+				leaves = append(leaves, &unionNode{envelope: p.Envelope(), polygons: MultiPolygon{p.(Polygon)}})
+			}
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	if len(leaves) == 0 {
+		return MultiPolygon{}, nil
+	}
+
+	root := leaves
+	for len(root) > 1 {
+		root = packUnionNodes(root)
+	}
+
+	u := &cascadedUnion{}
+	if workers > 1 {
+		u.workers = make(chan struct{}, workers-1)
+	}
+	return u.union(root[0])
+}
+
+//cascadedUnion computes the union of the nodes of an envelope tree
+type cascadedUnion struct {
+	workers chan struct{} //tokens of the additional goroutines, nil if the union is sequential
+}
+
+//union returns the union of the polygons below the node
+func (u *cascadedUnion) union(n *unionNode) (MultiPolygon, error) {
+	if len(n.children) == 0 {
+		return n.polygons, nil
+	}
+
+	results := make([]MultiPolygon, len(n.children))
+	errs := make([]error, len(n.children))
+	var wg sync.WaitGroup
+	for i, child := range n.children {
+		select {
+		case u.workers <- struct{}{}:
+			wg.Add(1)
+			go func(i int, child *unionNode) {
+				defer wg.Done()
+				results[i], errs[i] = u.union(child)
+				<-u.workers
+			}(i, child)
+		default:
+			//No goroutine available (or sequential union)
+			results[i], errs[i] = u.union(child)
+		}
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	//Balanced union of the children
+	for len(results) > 1 {
+		var merged []MultiPolygon
+		for i := 0; i < len(results); i += 2 {
+			if i+1 == len(results) {
+				merged = append(merged, results[i])
+				continue
+			}
+			m, err := unionPolygons(results[i], results[i+1])
+			if err != nil {
+				return nil, err
+			}
+			merged = append(merged, m)
+		}
+		results = merged
+	}
+	return results[0], nil
+}
+
+//unionPolygons returns the union of a and b as a MultiPolygon
+func unionPolygons(a, b MultiPolygon) (MultiPolygon, error) {
+	if envelopesDisjoint(a.Envelope(), b.Envelope()) {
+		mp := make(MultiPolygon, 0, len(a)+len(b))
+		return append(append(mp, a...), b...), nil
+	}
+	g, err := Union(a, b)
+	if err != nil {
+		return nil, err
+	}
+	var mp MultiPolygon
+	components(g, func(c Geometry) {
+		if p, ok := c.(Polygon); ok && !p.IsEmpty() {
+			mp = append(mp, p)
+		}
+	})
+	return mp, nil
+}
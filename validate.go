@@ -0,0 +1,511 @@
+// Copyright 2015 Simon HEGE. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package geom
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+//ValidityReason is the reason why a geometry is invalid
+type ValidityReason int
+
+//Reasons of invalidity
+const (
+	InvalidCoordinate    ValidityReason = iota //NaN or infinite value
+	TooFewPoints                               //less than 2 distinct points for a line, or 4 points (3 distinct) for a ring
+	RingNotClosed                              //first and last points of a ring differ
+	SelfIntersection                           //rings (or polygons of a MultiPolygon) crossing or overlapping each other or themselves
+	RingSelfTouch                              //ring touching itself at a point, without crossing
+	HoleOutsideShell                           //hole not inside the shell of its polygon
+	NestedHoles                                //hole inside another hole of the same polygon
+	NestedShells                               //polygon of a MultiPolygon inside another one
+	DisconnectedInterior                       //rings of a polygon touching each other so that its interior is split
+)
+
+func (r ValidityReason) String() string {
+	switch r {
+	case InvalidCoordinate:
+		return "Invalid coordinate"
+	case TooFewPoints:
+		return "Too few points"
+	case RingNotClosed:
+		return "Ring not closed"
+	case SelfIntersection:
+		return "Self-intersection"
+	case RingSelfTouch:
+		return "Ring self-touch"
+	case HoleOutsideShell:
+		return "Hole outside shell"
+	case NestedHoles:
+		return "Nested holes"
+	case NestedShells:
+		return "Nested shells"
+	case DisconnectedInterior:
+		return "Disconnected interior"
+	}
+	return "Unknown"
+}
+
+//ValidityError describes a problem found by Validate
+type ValidityError struct {
+	Reason    ValidityReason
+	Location  Point //Point where the problem was found
+	Component int   //Index of the point, line or polygon, counting the single components of the geometry in order
+	Ring      int   //Index of the ring in the polygon (0 for the shell), -1 for points and lines
+}
+
+func (e ValidityError) Error() string {
+	return fmt.Sprintf("%v at %v %v", e.Reason, e.Location.X, e.Location.Y)
+}
+
+//IsValid returns true if Validate finds no problem in g
+func IsValid(g Geometry) bool {
+	return len(Validate(g)) == 0
+}
+
+//Validate checks g against the OGC Simple Features validity rules and returns the problems found,
+//or nil if g is valid. Empty geometries are valid. All the dimension variants are handled,
+//and geometry collections are validated recursively (their components may overlap).
+//
+//Lines must have at least 2 distinct points. Polygon rings must be closed, have at least 3 distinct points,
+//neither cross nor touch themselves, and only touch each other at single points. Holes must be inside
+//their shell and not inside each other, and the rings must not touch so as to disconnect the interior
+//of the polygon (for example a hole touching the shell at two points). The polygons of a MultiPolygon may only touch at single points.
+//Coordinates must be finite, including Z and M values. Topological checks are skipped for polygons having
+//invalid coordinates or rings, and containment checks for polygons with intersecting rings.
+func Validate(g Geometry) []ValidityError {
+	v := &validator{}
+	if g != nil {
+		v.validate(g)
+	}
+	return v.errors
+}
+
+//validator accumulates the problems found in a geometry
+type validator struct {
+	errors    []ValidityError
+	component int //index of the next single component
+}
+
+func (v *validator) add(reason ValidityReason, pt Point, component, ring int) {
+	v.errors = append(v.errors, ValidityError{Reason: reason, Location: pt, Component: component, Ring: ring})
+}
+
+func (v *validator) validate(g Geometry) {
+	switch g.GeometryType() {
+	case "GeometryCollection", "MultiPoint", "MultiLineString":
+		for i := 0; i < g.NumGeometries(); i++ {
+			v.validate(g.GeometryN(i))
+		}
+	case "MultiPolygon":
+		polygons := make([]Geometry, g.NumGeometries())
+		for i := range polygons {
+			polygons[i] = g.GeometryN(i)
+		}
+		v.validatePolygons(polygons)
+	case "Polygon":
+		v.validatePolygons([]Geometry{g})
+	case "LineString":
+		if v.validCoordinates(g, v.component, -1) && !g.IsEmpty() {
+			points := pointsOf(g)
+			if len(removeRepeated(points)) < 2 {
+				v.add(TooFewPoints, points[0], v.component, -1)
+			}
+		}
+		v.component++
+	default:
+		v.validCoordinates(g, v.component, -1)
+		v.component++
+	}
+}
+
+//validCoordinates returns true if all the coordinates of g are finite, and reports the first invalid one otherwise
+func (v *validator) validCoordinates(g Geometry, component, ring int) bool {
+	finite := func(values ...float64) bool {
+		for _, f := range values {
+			if math.IsNaN(f) || math.IsInf(f, 0) {
+				return false
+			}
+		}
+		return true
+	}
+
+	var invalid *Point
+	switch t := g.(type) {
+	case GeometryZM:
+		t.IterateZM(func(points []PointZM) error {
+			for _, pt := range points {
+				if invalid == nil && !finite(pt.X, pt.Y, pt.Z, pt.M) {
+					invalid = &Point{X: pt.X, Y: pt.Y}
+				}
+			}
+			return nil
+		})
+	case GeometryZ:
+		t.IterateZ(func(points []PointZ) error {
+			for _, pt := range points {
+				if invalid == nil && !finite(pt.X, pt.Y, pt.Z) {
+					invalid = &Point{X: pt.X, Y: pt.Y}
+				}
+			}
+			return nil
+		})
+	case GeometryM:
+		t.IterateM(func(points []PointM) error {
+			for _, pt := range points {
+				if invalid == nil && !finite(pt.X, pt.Y, pt.M) {
+					invalid = &Point{X: pt.X, Y: pt.Y}
+				}
+			}
+			return nil
+		})
+	default:
+		g.Iterate(func(points []Point) error {
+			for _, pt := range points {
+				if invalid == nil && !finite(pt.X, pt.Y) {
+					pt := pt
+					invalid = &pt
+				}
+			}
+			return nil
+		})
+	}
+	if invalid != nil {
+		v.add(InvalidCoordinate, *invalid, component, ring)
+	}
+	return invalid == nil
+}
+
+//validPolygon is a polygon whose rings are closed and long enough
+type validPolygon struct {
+	component int
+	rings     [][]Point //without repeated points
+	envelope  *Envelope
+	crossed   bool //some rings intersect each other or themselves
+}
+
+//validSegment is a segment of a ring, identified by its polygon, ring and index in the ring
+type validSegment struct {
+	a, b                 Point
+	polygon, ring, index int
+}
+
+//validatePolygons checks polygons which must not overlap each other
+func (v *validator) validatePolygons(polygons []Geometry) {
+	var valid []*validPolygon
+	for _, g := range polygons {
+		component := v.component
+		v.component++
+		if g.IsEmpty() {
+			continue
+		}
+
+		s, isSurface := g.(Surface)
+		if !isSurface {
+			continue
+		}
+		rings := []Curve{s.ExteriorRing()}
+		for i := 0; i < s.NumInteriorRings(); i++ {
+			rings = append(rings, s.InteriorRingN(i))
+		}
+
+		p := &validPolygon{component: component, envelope: g.Envelope()}
+		ok := true
+		for ring, c := range rings {
+			points := pointsOf(c)
+			switch {
+			case !v.validCoordinates(c, component, ring):
+				ok = false
+			case len(points) == 0:
+				v.add(TooFewPoints, Point{}, component, ring)
+				ok = false
+			case points[0] != points[len(points)-1]:
+				v.add(RingNotClosed, points[0], component, ring)
+				ok = false
+			case len(removeRepeated(points)) < 4:
+				v.add(TooFewPoints, points[0], component, ring)
+				ok = false
+			}
+			p.rings = append(p.rings, removeRepeated(points))
+		}
+		if ok {
+			valid = append(valid, p)
+		}
+	}
+
+	v.checkIntersections(valid)
+	for _, p := range valid {
+		if !p.crossed {
+			v.checkHoles(p)
+		}
+	}
+	v.checkNestedShells(valid)
+}
+
+//checkIntersections reports the crossings and touches between all the rings of the polygons
+func (v *validator) checkIntersections(polygons []*validPolygon) {
+	var segments []validSegment
+	for i, p := range polygons {
+		for r, ring := range p.rings {
+			for k := 1; k < len(ring); k++ {
+				segments = append(segments, validSegment{a: ring[k-1], b: ring[k], polygon: i, ring: r, index: k - 1})
+			}
+		}
+	}
+	sort.Slice(segments, func(i, j int) bool {
+		return math.Min(segments[i].a.X, segments[i].b.X) < math.Min(segments[j].a.X, segments[j].b.X)
+	})
+
+	crossings := make(map[Point]bool)
+	crossing := func(s validSegment, pt Point) {
+		p := polygons[s.polygon]
+		v.add(SelfIntersection, pt, p.component, s.ring)
+		p.crossed = true
+		crossings[pt] = true
+	}
+
+	//Points where rings touch, with the two rings (given as polygon and ring indices)
+	type touch struct {
+		pt              Point
+		polygon1, ring1 int
+		polygon2, ring2 int
+	}
+	touches := make(map[touch]bool)
+	var touchOrder []touch
+
+	for i, s := range segments {
+		maxX := math.Max(s.a.X, s.b.X)
+		minY, maxY := math.Min(s.a.Y, s.b.Y), math.Max(s.a.Y, s.b.Y)
+		for _, t := range segments[i+1:] {
+			if math.Min(t.a.X, t.b.X) > maxX {
+				break
+			}
+			if math.Max(t.a.Y, t.b.Y) < minY || math.Min(t.a.Y, t.b.Y) > maxY {
+				continue
+			}
+			points := segmentIntersection(s.a, s.b, t.a, t.b)
+			if len(points) == 0 {
+				continue
+			}
+
+			if s.polygon == t.polygon && s.ring == t.ring {
+				last := len(polygons[s.polygon].rings[s.ring]) - 2
+				first, second := s, t
+				if first.index > second.index {
+					first, second = second, first
+				}
+				var shared *Point
+				switch {
+				case second.index == first.index+1:
+					shared = &first.b
+				case first.index == 0 && second.index == last:
+					shared = &first.a
+				}
+				if shared != nil {
+					//Adjacent segments only share their common point
+					for _, pt := range points {
+						if pt != *shared {
+							crossing(s, pt)
+							break
+						}
+					}
+					continue
+				}
+			}
+
+			if len(points) == 2 {
+				crossing(s, points[0])
+				continue
+			}
+			pt := points[0]
+			if pt != s.a && pt != s.b && pt != t.a && pt != t.b {
+				crossing(s, pt)
+				continue
+			}
+			key := touch{pt: pt, polygon1: s.polygon, ring1: s.ring, polygon2: t.polygon, ring2: t.ring}
+			if key.polygon1 > key.polygon2 || (key.polygon1 == key.polygon2 && key.ring1 > key.ring2) {
+				key.polygon1, key.ring1, key.polygon2, key.ring2 = key.polygon2, key.ring2, key.polygon1, key.ring1
+			}
+			if !touches[key] {
+				touches[key] = true
+				touchOrder = append(touchOrder, key)
+			}
+		}
+	}
+
+	//Touches are allowed between different rings, as long as they do not cross
+	var ringTouches []touch
+	for _, t := range touchOrder {
+		if crossings[t.pt] {
+			continue
+		}
+		ring1 := polygons[t.polygon1].rings[t.ring1]
+		ring2 := polygons[t.polygon2].rings[t.ring2]
+		passes1, passes2 := ringPasses(ring1, t.pt), ringPasses(ring2, t.pt)
+		same := t.polygon1 == t.polygon2 && t.ring1 == t.ring2
+
+		crossed := false
+		for i, p := range passes1 {
+			for j, q := range passes2 {
+				if (!same || i < j) && passesCross(t.pt, p, q) {
+					crossed = true
+				}
+			}
+		}
+		p := polygons[t.polygon1]
+		switch {
+		case crossed:
+			v.add(SelfIntersection, t.pt, p.component, t.ring1)
+			p.crossed = true
+			polygons[t.polygon2].crossed = true
+		case same:
+			v.add(RingSelfTouch, t.pt, p.component, t.ring1)
+			p.crossed = true
+		case t.polygon1 == t.polygon2:
+			ringTouches = append(ringTouches, t)
+		}
+	}
+
+	//The interior of a polygon is disconnected if its rings and touch points form a cycle:
+	//each ring is linked to its touch points, and a link between two nodes already connected closes a cycle
+	type node struct {
+		polygon, ring int
+		pt            Point
+		isPoint       bool
+	}
+	parent := make(map[node]node)
+	var find func(n node) node
+	find = func(n node) node {
+		p, ok := parent[n]
+		if !ok || p == n {
+			return n
+		}
+		root := find(p)
+		parent[n] = root
+		return root
+	}
+	links := make(map[node]bool)
+	disconnected := make(map[int]bool)
+	for _, t := range ringTouches {
+		if crossings[t.pt] || polygons[t.polygon1].crossed || disconnected[t.polygon1] {
+			continue
+		}
+		pt := node{polygon: t.polygon1, pt: t.pt, isPoint: true}
+		for _, ring := range []int{t.ring1, t.ring2} {
+			r := node{polygon: t.polygon1, ring: ring}
+			link := node{polygon: t.polygon1, ring: ring, pt: t.pt}
+			if links[link] {
+				continue
+			}
+			links[link] = true
+			if find(r) == find(pt) {
+				p := polygons[t.polygon1]
+				v.add(DisconnectedInterior, t.pt, p.component, t.ring2)
+				disconnected[t.polygon1] = true
+				break
+			}
+			parent[find(r)] = find(pt)
+		}
+	}
+}
+
+//ringPasses returns the previous and next points of each passage of the closed ring through pt
+func ringPasses(ring []Point, pt Point) [][2]Point {
+	var passes [][2]Point
+	n := len(ring) - 1
+	for k := 0; k < n; k++ {
+		a, b := ring[k], ring[k+1]
+		if a == pt {
+			prev := ring[n-1]
+			if k > 0 {
+				prev = ring[k-1]
+			}
+			passes = append(passes, [2]Point{prev, b})
+			continue
+		}
+		if b != pt && Orient2D(a, b, pt) == 0 && onSegment(a, b, pt) {
+			passes = append(passes, [2]Point{a, b})
+		}
+	}
+	return passes
+}
+
+//passesCross returns true if the passages p and q through v cross each other,
+//that is if the directions of q are on both sides of the angle formed by the directions of p
+func passesCross(v Point, p, q [2]Point) bool {
+	angle := func(pt Point) float64 {
+		return math.Atan2(pt.Y-v.Y, pt.X-v.X)
+	}
+	a0, a1 := angle(p[0]), angle(p[1])
+	side := func(pt Point) int {
+		a := angle(pt)
+		if a == a0 || a == a1 {
+			return 0
+		}
+		if math.Mod(a-a0+4*math.Pi, 2*math.Pi) < math.Mod(a1-a0+4*math.Pi, 2*math.Pi) {
+			return 1
+		}
+		return -1
+	}
+	return side(q[0])*side(q[1]) < 0
+}
+
+//ringPoint returns a point of the ring not located on the boundary of the other ring, if any
+func ringPoint(ring, other []Point) (Point, Location, bool) {
+	for _, pt := range ring {
+		if loc := locateInRing(pt, other); loc != Boundary {
+			return pt, loc, true
+		}
+	}
+	//Ring made of points on the other ring: test the middle of its segments
+	for k := 1; k < len(ring); k++ {
+		pt := Point{X: (ring[k-1].X + ring[k].X) / 2, Y: (ring[k-1].Y + ring[k].Y) / 2}
+		if loc := locateInRing(pt, other); loc != Boundary {
+			return pt, loc, true
+		}
+	}
+	return Point{}, Boundary, false
+}
+
+//checkHoles reports the holes outside the shell or inside another hole
+func (v *validator) checkHoles(p *validPolygon) {
+	shell := p.rings[0]
+	for i, hole := range p.rings[1:] {
+		if pt, loc, ok := ringPoint(hole, shell); ok && loc == Exterior {
+			v.add(HoleOutsideShell, pt, p.component, i+1)
+			continue
+		}
+		for j, other := range p.rings[1:] {
+			if j == i {
+				continue
+			}
+			if pt, loc, ok := ringPoint(hole, other); ok && loc == Interior {
+				v.add(NestedHoles, pt, p.component, i+1)
+				break
+			}
+		}
+	}
+}
+
+//checkNestedShells reports the polygons whose shell is inside another polygon
+func (v *validator) checkNestedShells(polygons []*validPolygon) {
+	for i, p := range polygons {
+		if p.crossed {
+			continue
+		}
+		for j, q := range polygons {
+			if i == j || q.crossed || envelopesDisjoint(p.envelope, q.envelope) {
+				continue
+			}
+			pt, loc, ok := ringPoint(p.rings[0], q.rings[0])
+			if ok && loc == Interior && locateInRings(pt, q.rings) == Interior {
+				v.add(NestedShells, pt, p.component, 0)
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,81 @@
+// Copyright 2015 Simon HEGE. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package geom
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+//ring returns a closed ring through the given x, y coordinates
+func ring(coords ...float64) LineString {
+	var l LineString
+	for i := 0; i+1 < len(coords); i += 2 {
+		l = append(l, Point{X: coords[i], Y: coords[i+1]})
+	}
+	return append(l, l[0])
+}
+
+func TestValidate(t *testing.T) {
+	square := ring(0, 0, 10, 0, 10, 10, 0, 10)
+	tests := []struct {
+		name string
+		g    Geometry
+		want []ValidityReason
+	}{
+		{"point", &Point{X: 1, Y: 2}, nil},
+		{"empty polygon", Polygon{}, nil},
+		{"square", Polygon{square}, nil},
+		{"hole", Polygon{square, ring(2, 2, 2, 4, 4, 4, 4, 2)}, nil},
+		{"hole touching shell once", Polygon{square, ring(5, 0, 3, 3, 7, 3)}, nil},
+		{"invalid coordinate", &Point{X: math.NaN()}, []ValidityReason{InvalidCoordinate}},
+		{"invalid Z", LineStringZ{{Point: Point{X: 0, Y: 0}, Z: math.Inf(1)}, {Point: Point{X: 1, Y: 1}}}, []ValidityReason{InvalidCoordinate}},
+		{"line too short", LineString{{X: 1, Y: 1}, {X: 1, Y: 1}}, []ValidityReason{TooFewPoints}},
+		{"ring too short", Polygon{ring(0, 0, 1, 1)}, []ValidityReason{TooFewPoints}},
+		{"ring not closed", Polygon{square[:4]}, []ValidityReason{RingNotClosed}},
+		{"bow-tie", Polygon{ring(0, 0, 10, 10, 10, 0, 0, 10)}, []ValidityReason{SelfIntersection}},
+		{"self-touching ring", Polygon{ring(0, 0, 10, 0, 5, 5, 10, 10, 0, 10, 5, 5)}, []ValidityReason{RingSelfTouch}},
+		{"hole outside shell", Polygon{square, ring(20, 20, 20, 22, 22, 22)}, []ValidityReason{HoleOutsideShell}},
+		{"nested holes", Polygon{square, ring(1, 1, 1, 9, 9, 9, 9, 1), ring(2, 2, 2, 4, 4, 4, 4, 2)}, []ValidityReason{NestedHoles}},
+		{"crossing hole", Polygon{square, ring(5, 5, 5, 15, 8, 15, 8, 5)}, []ValidityReason{SelfIntersection, SelfIntersection}},
+		{"nested shells", MultiPolygon{{square}, {ring(2, 2, 2, 4, 4, 4, 4, 2)}}, []ValidityReason{NestedShells}},
+		{"touching shells", MultiPolygon{{square}, {ring(10, 10, 20, 10, 20, 20)}}, nil},
+		{"overlapping shells", MultiPolygon{{square}, {ring(5, 5, 15, 5, 15, 15, 5, 15)}}, []ValidityReason{SelfIntersection, SelfIntersection}},
+		{"diamond hole", Polygon{square, ring(5, 0, 10, 5, 5, 10, 0, 5)}, []ValidityReason{DisconnectedInterior}},
+		{"hole touching shell twice", Polygon{square, ring(0, 5, 5, 0, 5, 5)}, []ValidityReason{DisconnectedInterior}},
+		{"chain of holes", Polygon{square, ring(0, 5, 3, 3, 5, 5, 3, 7), ring(5, 5, 7, 3, 10, 5, 7, 7)}, []ValidityReason{DisconnectedInterior}},
+		{"holes touching once", Polygon{square, ring(2, 2, 2, 5, 5, 5), ring(5, 5, 8, 5, 8, 8)}, nil},
+		{"collection", GeometryCollection{Polygon{square}, LineString{{X: 1, Y: 1}}}, []ValidityReason{TooFewPoints}},
+	}
+	for _, tt := range tests {
+		var got []ValidityReason
+		for _, err := range Validate(tt.g) {
+			got = append(got, err.Reason)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: Validate() reasons = %v, want %v", tt.name, got, tt.want)
+		}
+		if IsValid(tt.g) != (tt.want == nil) {
+			t.Errorf("%s: IsValid() = %v", tt.name, IsValid(tt.g))
+		}
+	}
+}
+
+func TestValidateLocation(t *testing.T) {
+	g := Polygon{ring(0, 0, 10, 0, 10, 10, 0, 10), ring(5, 0, 10, 5, 5, 10, 0, 5)}
+	errs := Validate(g)
+	if len(errs) != 1 {
+		t.Fatalf("Validate() = %v, want a single error", errs)
+	}
+	e := errs[0]
+	if e.Component != 0 || e.Ring != 1 {
+		t.Errorf("Validate() = %+v, want component 0 and ring 1", e)
+	}
+	touches := map[Point]bool{{X: 5, Y: 0}: true, {X: 10, Y: 5}: true, {X: 5, Y: 10}: true, {X: 0, Y: 5}: true}
+	if !touches[e.Location] {
+		t.Errorf("Validate() location = %v, want one of the touch points", e.Location)
+	}
+}